@@ -0,0 +1,116 @@
+package cache
+
+import "iter"
+
+// All returns an iterator over the entries of the cache that haven't expired,
+// from the next to be evicted to the last one.
+// The iterator works on a copy of the entries taken when iteration starts: the cache can be used
+// during iteration, and reading entries this way doesn't count as a use for the eviction policy.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range c.live() {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys of the cache that haven't expired,
+// from the next to be evicted to the last one. See All.
+func (c *Cache[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, e := range c.live() {
+			if !yield(e.key) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of entries of the cache that haven't expired.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	count := 0
+	for _, e := range c.data {
+		if !e.expired(now) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Clear removes all the entries, with the reason EvictionReasonDeleted.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.clear()
+}
+
+// UpsertMany overwrites the values of all the given keys, in order, under a single lock.
+// The entries expire after the default TTL of the cache.
+// The iterator is consumed before the lock is taken, so it may use the cache, e.g. be the result of All.
+func (c *Cache[K, V]) UpsertMany(entries iter.Seq2[K, V]) {
+	var pairs []keyValue[K, V]
+	for key, value := range entries {
+		pairs = append(pairs, keyValue[K, V]{key: key, value: value})
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, kv := range pairs {
+		c.upsert(kv.key, kv.value, c.ttl, c.cost(kv.value))
+	}
+}
+
+// DeleteMany removes the entries for all the given keys, under a single lock.
+func (c *Cache[K, V]) DeleteMany(keys ...K) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		delete(c.failures, key)
+		c.invalidateLoad(key)
+		c.deleteKeyValue(key, EvictionReasonDeleted)
+	}
+}
+
+// keyValue is a copy of an entry of the cache.
+type keyValue[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// live returns a copy of the entries that haven't expired, in the order of the eviction policy.
+func (c *Cache[K, V]) live() []keyValue[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	ranking := c.policy.ranking()
+	entries := make([]keyValue[K, V], 0, len(ranking))
+	for _, rk := range ranking {
+		e := c.data[rk.key]
+		if !e.expired(now) {
+			entries = append(entries, keyValue[K, V]{key: rk.key, value: e.value})
+		}
+	}
+
+	return entries
+}
+
+// clear removes all the entries. The caller must hold the lock.
+func (c *Cache[K, V]) clear() {
+	for key := range c.data {
+		c.deleteKeyValue(key, EvictionReasonDeleted)
+	}
+
+	clear(c.failures)
+	clear(c.loads)
+}
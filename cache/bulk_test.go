@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"goprojects/cache"
+	"goprojects/cache/cachetest"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_All(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[int, string](5, time.Minute, cache.WithClock[int, string](clock))
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+	c.UpsertWithTTL(3, "drei", time.Nanosecond)
+	c.Upsert(4, "vier")
+
+	clock.Advance(time.Millisecond)
+
+	got := make(map[int]string)
+	for k, v := range c.All() {
+		got[k] = v
+		// The cache can be used during iteration.
+		c.Upsert(k+10, v)
+	}
+
+	expected := map[int]string{1: "eins", 2: "zwei", 4: "vier"}
+	assert.Equal(t, expected, got)
+}
+
+func TestCache_Keys(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+	c.Upsert(3, "drei")
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+
+	assert.Equal(t, []int{3, 1, 2}, slices.Collect(c.Keys()))
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestCache_UpsertMany_DeleteMany(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+	c.UpsertMany(maps.All(map[int]string{1: "eins", 2: "zwei", 3: "drei"}))
+	assert.Equal(t, 3, c.Len())
+
+	c.DeleteMany(1, 3, 42)
+
+	assert.Equal(t, []int{2}, slices.Collect(c.Keys()))
+}
+
+func TestCache_UpsertMany_fromCache(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+	c.UpsertMany(maps.All(map[int]string{1: "eins", 2: "zwei"}))
+
+	// The iterator reads the cache it is given to.
+	c.UpsertMany(func(yield func(int, string) bool) {
+		for key, value := range c.All() {
+			if !yield(key+10, strings.ToUpper(value)) {
+				return
+			}
+		}
+	})
+
+	got := maps.Collect(c.All())
+	assert.Equal(t, map[int]string{1: "eins", 2: "zwei", 11: "EINS", 12: "ZWEI"}, got)
+}
+
+func TestCache_Clear(t *testing.T) {
+	t.Parallel()
+
+	var reasons []cache.EvictionReason
+	c := cache.New[int, string](5, time.Minute, cache.WithOnEvict(func(_ int, _ string, reason cache.EvictionReason) {
+		reasons = append(reasons, reason)
+	}))
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+
+	c.Clear()
+
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, []cache.EvictionReason{cache.EvictionReasonDeleted, cache.EvictionReasonDeleted}, reasons)
+}
@@ -1,90 +1,293 @@
 package cache
 
 import (
-	"slices"
 	"sync"
 	"time"
 )
 
 // Cache is a key-value storage.
 type Cache[K comparable, V any] struct {
-	ttl               time.Duration
-	mu                sync.Mutex
-	data              map[K]entryWithTimeout[V]
-	maxSize           int
-	chronologicalKeys []K
+	ttl     time.Duration
+	mu      sync.Mutex
+	data    map[K]entryWithTimeout[V]
+	maxSize int
+	policy  evictionPolicy[K]
+
+	maxCost   int64
+	totalCost int64
+	sizer     func(V) int64
+
+	loads    map[K]*load[V]
+	failures map[K]failure
+	errorTTL time.Duration
+
+	onEvict   func(key K, value V, reason EvictionReason)
+	evictions []eviction[K, V]
+
+	counters counters
+
+	clock Clock
+
+	sliding     bool
+	maxLifetime time.Duration
+
+	grace       time.Duration
+	staleLoader func(K) (V, error)
+
+	janitorInterval time.Duration
+	stopJanitor     chan struct{}
+	janitorDone     chan struct{}
+	closeOnce       sync.Once
 }
 
+// NoExpiration is a TTL for entries that never expire.
+const NoExpiration time.Duration = -1
+
 type entryWithTimeout[V any] struct {
-	value   V
+	value V
+	// expires is the zero time for entries that never expire.
 	expires time.Time
+	cost    int64
+	// ttl and created are used to extend the expiration of entries with a sliding TTL.
+	ttl     time.Duration
+	created time.Time
+}
+
+// expired returns whether the entry has expired at the given time.
+func (e entryWithTimeout[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && e.expires.Before(now)
+}
+
+// expiry returns the expiration time of an entry written now with the given TTL.
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl == NoExpiration {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
 }
 
 // New creates a usable Cache with an initialized data.
-func New[K comparable, V any](maxSize int, ttl time.Duration) Cache[K, V] {
-	return Cache[K, V]{
-		ttl:               ttl,
-		data:              make(map[K]entryWithTimeout[V]),
-		maxSize:           maxSize,
-		chronologicalKeys: make([]K, 0, maxSize),
+// The cache holds at most maxSize entries. Use 0 for no maximum number of entries,
+// e.g. when the capacity is expressed as a cost with WithMaxCost.
+// The ttl applies to entries written with Upsert. Use NoExpiration to keep them forever.
+// Give it a list of options to tune it at your will.
+// The default eviction policy is FIFO.
+// If a janitor is requested with WithJanitor, call Close to stop it.
+func New[K comparable, V any](maxSize int, ttl time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		ttl:     ttl,
+		data:    make(map[K]entryWithTimeout[V]),
+		maxSize: maxSize,
+		policy:  newPolicy[K](FIFO, maxSize),
+
+		loads:    make(map[K]*load[V]),
+		failures: make(map[K]failure),
+
+		clock: systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.janitorInterval > 0 {
+		c.startJanitor(c.janitorInterval)
 	}
+
+	return c
 }
 
 // Read returns the associated value for a key,
 // and a boolean to false if the key is absent.
 func (c *Cache[K, V]) Read(key K) (V, bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+
+	v, found, _ := c.read(key)
+	return v, found
+}
 
+// read returns the associated value for a key, and removes it if it has expired.
+// Expired entries within the grace window of WithStaleWhileRevalidate are returned as stale,
+// and refreshed in the background.
+// The caller must hold the lock.
+func (c *Cache[K, V]) read(key K) (value V, found bool, stale bool) {
 	var zeroV V
 
+	now := c.now()
+
 	v, ok := c.data[key]
 	switch {
 	case !ok:
-		return zeroV, false
-	case v.expires.Before(time.Now()):
-		c.deleteKeyValue(key)
-		return zeroV, false
+		c.counters.misses.Add(1)
+		return zeroV, false, false
+	case v.expired(now) && c.stale(v, now):
+		c.counters.hits.Add(1)
+		c.policy.access(key)
+		c.revalidate(key)
+		return v.value, true, true
+	case v.expired(now):
+		c.counters.misses.Add(1)
+		c.deleteKeyValue(key, EvictionReasonExpired)
+		return zeroV, false, false
 	default:
-		return v.value, true
+		c.counters.hits.Add(1)
+		c.policy.access(key)
+		if c.sliding {
+			c.slide(key, v, now)
+		}
+		return v.value, true, false
+	}
+}
+
+// slide extends the expiration of an entry that was just read, by its TTL,
+// without exceeding its maximum lifetime, if any.
+func (c *Cache[K, V]) slide(key K, v entryWithTimeout[V], now time.Time) {
+	if v.expires.IsZero() {
+		return
 	}
+
+	v.expires = now.Add(v.ttl)
+	if c.maxLifetime > 0 {
+		v.expires = minTime(v.expires, v.created.Add(c.maxLifetime))
+	}
+
+	c.data[key] = v
 }
 
-// Upsert overwrites the value for a given key.
+// minTime returns the earliest of two times.
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+// Upsert overwrites the value for a given key. The entry expires after the default TTL of the cache.
 func (c *Cache[K, V]) Upsert(key K, value V) {
+	c.UpsertWithTTL(key, value, c.ttl)
+}
+
+// UpsertWithTTL overwrites the value for a given key. The entry expires after the given TTL,
+// or never if the TTL is NoExpiration.
+func (c *Cache[K, V]) UpsertWithTTL(key K, value V, ttl time.Duration) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+
+	c.upsert(key, value, ttl, c.cost(value))
+}
+
+// upsert overwrites the value for a given key, evicting entries if the cache is full.
+// Costs lower than 1 count as 1, so that every entry takes room in the budget.
+// The caller must hold the lock.
+func (c *Cache[K, V]) upsert(key K, value V, ttl time.Duration, cost int64) {
+	delete(c.failures, key)
+	c.invalidateLoad(key)
+
+	cost = max(cost, 1)
+
+	if c.maxCost > 0 && cost > c.maxCost {
+		// The entry can't fit, even in an empty cache: the previous value is removed, and the new one is rejected.
+		c.deleteKeyValue(key, EvictionReasonCapacity)
+		c.notifyEviction(key, value, EvictionReasonCapacity)
+		return
+	}
 
 	_, alreadyPresent := c.data[key]
-	switch {
-	case alreadyPresent:
-		c.deleteKeyValue(key)
-	case len(c.data) == c.maxSize:
-		c.deleteKeyValue(c.chronologicalKeys[0])
+	if alreadyPresent {
+		c.updateKeyValue(key, value, ttl, cost)
+		if c.overBudget(0, 0) {
+			// The entry just written mustn't be its own victim, which the LFU policy could pick.
+			c.policy.remove(key)
+			c.evict(0, 0)
+			c.policy.add(key)
+		}
+		return
 	}
 
-	c.addKeyValue(key, value)
+	c.evict(1, cost)
+	c.addKeyValue(key, value, ttl, cost)
+}
+
+// overBudget returns whether the given number of additional entries and cost exceed the size or the cost of the cache.
+// The caller must hold the lock.
+func (c *Cache[K, V]) overBudget(entries int, cost int64) bool {
+	return (c.maxSize > 0 && len(c.data)+entries > c.maxSize) || (c.maxCost > 0 && c.totalCost+cost > c.maxCost)
+}
+
+// evict discards entries, following the eviction policy, until the cache has room
+// for the given number of additional entries and cost.
+// The caller must hold the lock.
+func (c *Cache[K, V]) evict(entries int, cost int64) {
+	for c.overBudget(entries, cost) {
+		victim, ok := c.policy.victim()
+		if !ok {
+			return
+		}
+
+		c.deleteKeyValue(victim, EvictionReasonCapacity)
+	}
 }
 
 // Delete removes the entry for the given key.
 func (c *Cache[K, V]) Delete(key K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	c.deleteKeyValue(key)
+	delete(c.failures, key)
+	c.invalidateLoad(key)
+	c.deleteKeyValue(key, EvictionReasonDeleted)
 }
 
 // addKeyValue inserts a key and its value into the cache.
-func (c *Cache[K, V]) addKeyValue(key K, value V) {
+func (c *Cache[K, V]) addKeyValue(key K, value V, ttl time.Duration, cost int64) {
+	now := c.now()
 	c.data[key] = entryWithTimeout[V]{
 		value:   value,
-		expires: time.Now().Add(c.ttl),
+		expires: expiry(now, ttl),
+		cost:    cost,
+		ttl:     ttl,
+		created: now,
 	}
-	c.chronologicalKeys = append(c.chronologicalKeys, key)
+	c.totalCost += cost
+	c.policy.add(key)
+}
+
+// updateKeyValue overwrites the value of a key already present in the cache.
+func (c *Cache[K, V]) updateKeyValue(key K, value V, ttl time.Duration, cost int64) {
+	now := c.now()
+	previous := c.data[key]
+	c.notifyEviction(key, previous.value, EvictionReasonReplaced)
+	c.data[key] = entryWithTimeout[V]{
+		value:   value,
+		expires: expiry(now, ttl),
+		cost:    cost,
+		ttl:     ttl,
+		created: now,
+	}
+	c.totalCost += cost - previous.cost
+	c.policy.update(key)
 }
 
 // deleteKeyValue removes a key and its associated value from the cache.
-func (c *Cache[K, V]) deleteKeyValue(key K) {
-	c.chronologicalKeys = slices.DeleteFunc(c.chronologicalKeys, func(k K) bool { return k == key })
+func (c *Cache[K, V]) deleteKeyValue(key K, reason EvictionReason) {
+	v, ok := c.data[key]
+	if !ok {
+		return
+	}
+
+	c.policy.remove(key)
 	delete(c.data, key)
+	c.totalCost -= v.cost
+
+	switch reason {
+	case EvictionReasonExpired:
+		c.counters.expirations.Add(1)
+	case EvictionReasonCapacity:
+		c.counters.evictions.Add(1)
+	}
+
+	c.notifyEviction(key, v.value, reason)
 }
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"container/list"
+	"goprojects/cache/cachetest"
 	"slices"
 	"testing"
 	"time"
@@ -9,13 +11,19 @@ import (
 )
 
 func TestCache(t *testing.T) {
-	c := New[int, int](3, time.Second)
+	clock := cachetest.NewClock(time.Now())
+	c := New[int, int](3, time.Second, WithClock[int, int](clock))
 
 	if c.data == nil {
 		t.Error("Data content not initialized")
 	}
 
-	if c.chronologicalKeys == nil {
+	fifo, ok := c.policy.(*fifoPolicy[int])
+	if !ok {
+		t.Fatalf("Default policy should be FIFO, got %T", c.policy)
+	}
+
+	if fifo.keys == nil || fifo.elements == nil {
 		t.Error("List of keys not initialized")
 	}
 
@@ -24,8 +32,8 @@ func TestCache(t *testing.T) {
 	c.Upsert(3, 30)
 
 	expectedKeys := []int{1, 2, 3}
-	if !slices.Equal(c.chronologicalKeys, expectedKeys) {
-		t.Errorf("List of keys should be %v, instead we have %v", expectedKeys, c.chronologicalKeys)
+	if got := listKeys[int](fifo.keys); !slices.Equal(got, expectedKeys) {
+		t.Errorf("List of keys should be %v, instead we have %v", expectedKeys, got)
 	}
 
 	dataKeys := maps.Keys(c.data)
@@ -36,8 +44,8 @@ func TestCache(t *testing.T) {
 
 	c.Upsert(2, 31)
 	expectedKeys = []int{1, 3, 2}
-	if !slices.Equal(c.chronologicalKeys, expectedKeys) {
-		t.Errorf("List of keys should be %v, instead we have %v", expectedKeys, c.chronologicalKeys)
+	if got := listKeys[int](fifo.keys); !slices.Equal(got, expectedKeys) {
+		t.Errorf("List of keys should be %v, instead we have %v", expectedKeys, got)
 	}
 
 	c.Delete(3)
@@ -60,7 +68,7 @@ func TestCache(t *testing.T) {
 		t.Error("Value of key 1 should be 10")
 	}
 
-	time.Sleep(time.Second)
+	clock.Advance(time.Second + time.Nanosecond)
 
 	value, found = c.Read(1)
 	if found {
@@ -70,3 +78,81 @@ func TestCache(t *testing.T) {
 		t.Errorf("Value of notfound key 1 should be 0, got %d", value)
 	}
 }
+
+// listKeys returns the keys held in a list, in order.
+func listKeys[K any](l *list.List) []K {
+	keys := make([]K, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(K))
+	}
+
+	return keys
+}
+
+func TestCache_slidingTTL(t *testing.T) {
+	tt := map[string]struct {
+		maxLifetime time.Duration
+		// reads are the delays between successive reads, all of them successful.
+		reads []time.Duration
+		// expiredAfter is the delay after the last read after which the entry has expired.
+		expiredAfter time.Duration
+	}{
+		"reads extend the expiration": {
+			reads:        []time.Duration{50 * time.Second, 50 * time.Second, 50 * time.Second},
+			expiredAfter: time.Minute + time.Second,
+		},
+		"expiration is capped by the maximum lifetime": {
+			maxLifetime:  2 * time.Minute,
+			reads:        []time.Duration{50 * time.Second, 50 * time.Second},
+			expiredAfter: 20*time.Second + time.Second,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			clock := cachetest.NewClock(time.Date(2024, 4, 25, 12, 0, 0, 0, time.UTC))
+
+			c := New[string, string](3, time.Minute,
+				WithSlidingTTL[string, string](tc.maxLifetime),
+				WithClock[string, string](clock),
+			)
+
+			c.Upsert("session", "active")
+
+			for _, delay := range tc.reads {
+				clock.Advance(delay)
+				if _, found := c.Read("session"); !found {
+					t.Fatalf("Entry should still be there at %s", clock.Now())
+				}
+			}
+
+			clock.Advance(tc.expiredAfter - 2*time.Second)
+			if c.data["session"].expired(clock.Now()) {
+				t.Fatalf("Entry shouldn't have expired yet at %s", clock.Now())
+			}
+
+			clock.Advance(2 * time.Second)
+			if _, found := c.Read("session"); found {
+				t.Errorf("Entry should have expired at %s", clock.Now())
+			}
+		})
+	}
+}
+
+func TestCache_slidingTTL_disabled(t *testing.T) {
+	clock := cachetest.NewClock(time.Date(2024, 4, 25, 12, 0, 0, 0, time.UTC))
+
+	c := New[string, string](3, time.Minute, WithClock[string, string](clock))
+
+	c.Upsert("session", "active")
+
+	clock.Advance(50 * time.Second)
+	if _, found := c.Read("session"); !found {
+		t.Fatal("Entry should still be there")
+	}
+
+	clock.Advance(50 * time.Second)
+	if _, found := c.Read("session"); found {
+		t.Error("Reading the entry shouldn't have extended its expiration")
+	}
+}
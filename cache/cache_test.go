@@ -3,6 +3,7 @@ package cache_test
 import (
 	"fmt"
 	"goprojects/cache"
+	"goprojects/cache/cachetest"
 	"sync"
 	"testing"
 	"time"
@@ -91,7 +92,8 @@ func TestCache_Parallel(t *testing.T) {
 func TestCache_TTL(t *testing.T) {
 	t.Parallel()
 
-	c := cache.New[string, string](5, time.Millisecond*100)
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Millisecond*100, cache.WithClock[string, string](clock))
 	c.Upsert("Norwegian", "Blue")
 
 	// Check the item is there
@@ -99,7 +101,7 @@ func TestCache_TTL(t *testing.T) {
 	assert.True(t, found)
 	assert.Equal(t, "Blue", got)
 
-	time.Sleep(time.Millisecond * 200)
+	clock.Advance(time.Millisecond * 200)
 
 	// We've waited too long - the value's metabolic processes are now history.
 	got, found = c.Read("Norwegian")
@@ -132,3 +134,135 @@ func TestCache_MaxSize(t *testing.T) {
 	assert.False(t, found)
 	assert.Equal(t, 0, got)
 }
+
+func TestCache_LRU(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, int](3, time.Minute, cache.WithPolicy[int, int](cache.LRU))
+
+	c.Upsert(1, 1)
+	c.Upsert(2, 2)
+	c.Upsert(3, 3)
+
+	// Reading 1 makes it the most recently used.
+	_, found := c.Read(1)
+	assert.True(t, found)
+
+	// Adding a fourth element will discard the least recently used - 2 in this case.
+	c.Upsert(4, 4)
+
+	_, found = c.Read(2)
+	assert.False(t, found)
+
+	got, found := c.Read(1)
+	assert.True(t, found)
+	assert.Equal(t, 1, got)
+}
+
+func TestCache_LFU(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, int](3, time.Minute, cache.WithPolicy[int, int](cache.LFU))
+
+	c.Upsert(1, 1)
+	c.Upsert(2, 2)
+	c.Upsert(3, 3)
+
+	// 1 and 3 are read more often than 2.
+	for i := 0; i < 3; i++ {
+		_, _ = c.Read(1)
+		_, _ = c.Read(3)
+	}
+
+	// Adding a fourth element will discard the least frequently used - 2 in this case.
+	c.Upsert(4, 4)
+
+	_, found := c.Read(2)
+	assert.False(t, found)
+
+	got, found := c.Read(3)
+	assert.True(t, found)
+	assert.Equal(t, 3, got)
+}
+
+// benchmarkSizes are the numbers of entries used to prove operations don't depend on the size of the cache.
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+// newFullCache returns a cache of the given size, filled with keys from 0 to size-1.
+func newFullCache(size int, policy cache.Policy) *cache.Cache[int, int] {
+	c := cache.New[int, int](size, time.Hour, cache.WithPolicy[int, int](policy))
+	for i := 0; i < size; i++ {
+		c.Upsert(i, i)
+	}
+
+	return c
+}
+
+func BenchmarkCache_Upsert_existing(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, policy := range []cache.Policy{cache.FIFO, cache.LRU, cache.LFU} {
+			b.Run(fmt.Sprintf("size=%d/policy=%s", size, policy), func(b *testing.B) {
+				c := newFullCache(size, policy)
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					c.Upsert(i%size, i)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCache_Upsert_evict(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, policy := range []cache.Policy{cache.FIFO, cache.LRU, cache.LFU} {
+			b.Run(fmt.Sprintf("size=%d/policy=%s", size, policy), func(b *testing.B) {
+				c := newFullCache(size, policy)
+				b.ResetTimer()
+
+				// Every key is new, so every Upsert evicts an entry.
+				for i := 0; i < b.N; i++ {
+					c.Upsert(size+i, i)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCache_Delete(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			c := newFullCache(size, cache.FIFO)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				// Put the key back, so that there is always something to delete.
+				c.Delete(i % size)
+				c.Upsert(i%size, i)
+			}
+		})
+	}
+}
+
+func TestCache_UpsertWithTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Millisecond*100, cache.WithClock[string, string](clock))
+	c.Upsert("default", "short")
+	c.UpsertWithTTL("long", "lived", time.Minute)
+	c.UpsertWithTTL("forever", "young", cache.NoExpiration)
+
+	clock.Advance(time.Millisecond * 200)
+
+	_, found := c.Read("default")
+	assert.False(t, found)
+
+	got, found := c.Read("long")
+	assert.True(t, found)
+	assert.Equal(t, "lived", got)
+
+	got, found = c.Read("forever")
+	assert.True(t, found)
+	assert.Equal(t, "young", got)
+}
@@ -0,0 +1,22 @@
+package cachetest_test
+
+import (
+	"goprojects/cache/cachetest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 4, 25, 12, 0, 0, 0, time.UTC)
+	clock := cachetest.NewClock(start)
+
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
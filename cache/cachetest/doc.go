@@ -0,0 +1,9 @@
+// Package cachetest provides tools to test code that uses the cache package.
+//
+// Its Clock replaces the real time, so that expirations can be tested without waiting:
+//
+//	clock := cachetest.NewClock(time.Now())
+//	c := cache.New[K, V](maxSize, ttl, cache.WithClock[K, V](clock))
+//	...
+//	clock.Advance(ttl)
+package cachetest
@@ -0,0 +1,22 @@
+package cache
+
+import "time"
+
+// Clock tells the current time. The cache uses it to compute expirations.
+// A fake implementation, to be used in tests, is available in the cachetest package.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, based on time.Now.
+type systemClock struct{}
+
+// Now implements the Clock interface.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// now returns the current time, according to the clock of the cache.
+func (c *Cache[K, V]) now() time.Time {
+	return c.clock.Now()
+}
@@ -0,0 +1,19 @@
+package cache
+
+// UpsertWithCost overwrites the value for a given key, with the given cost instead of the one computed by the sizer.
+// A cost lower than 1 counts as 1. The entry expires after the default TTL of the cache.
+func (c *Cache[K, V]) UpsertWithCost(key K, value V, cost int64) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.upsert(key, value, c.ttl, cost)
+}
+
+// cost returns the cost of a value, computed by the sizer, if any.
+func (c *Cache[K, V]) cost(value V) int64 {
+	if c.sizer == nil {
+		return 1
+	}
+
+	return c.sizer(value)
+}
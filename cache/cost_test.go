@@ -0,0 +1,87 @@
+package cache_test
+
+import (
+	"goprojects/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_MaxCost(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[string, string](0, time.Minute,
+		cache.WithMaxCost[string, string](10),
+		cache.WithSizer[string, string](func(v string) int64 { return int64(len(v)) }),
+	)
+
+	c.Upsert("a", "aaa")
+	c.Upsert("b", "bbb")
+	c.Upsert("c", "ccc")
+	assert.Equal(t, int64(9), c.Stats().Cost)
+
+	// Making room for 7 requires evicting both a and b.
+	c.Upsert("d", "ddddddd")
+
+	_, found := c.Read("a")
+	assert.False(t, found)
+	_, found = c.Read("b")
+	assert.False(t, found)
+
+	got, found := c.Read("c")
+	assert.True(t, found)
+	assert.Equal(t, "ccc", got)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(2), stats.Evictions)
+	assert.Equal(t, int64(10), stats.Cost)
+
+	// Growing an existing entry evicts the others.
+	c.Upsert("d", "dddddddddd")
+	_, found = c.Read("c")
+	assert.False(t, found)
+	assert.Equal(t, int64(10), c.Stats().Cost)
+}
+
+func TestCache_UpsertWithCost(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](0, time.Minute, cache.WithMaxCost[int, string](100))
+
+	c.UpsertWithCost(1, "eins", 60)
+	c.UpsertWithCost(2, "zwei", 40)
+	c.Upsert(3, "drei") // costs 1 by default, evicts 1
+
+	_, found := c.Read(1)
+	assert.False(t, found)
+
+	// Too expensive to be stored at all: the previous value is removed, nothing else is.
+	c.UpsertWithCost(2, "too big", 101)
+
+	_, found = c.Read(2)
+	assert.False(t, found)
+
+	got, found := c.Read(3)
+	assert.True(t, found)
+	assert.Equal(t, "drei", got)
+	assert.Equal(t, int64(1), c.Stats().Cost)
+}
+
+func TestCache_MaxCost_nonPositive(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](0, time.Minute,
+		cache.WithMaxCost[int, string](10),
+		cache.WithSizer[int, string](func(string) int64 { return -5 }),
+	)
+
+	// Costs lower than 1 count as 1: the budget is still enforced.
+	for i := range 100 {
+		c.Upsert(i, "free")
+	}
+	c.UpsertWithCost(100, "free", 0)
+
+	assert.Equal(t, 10, c.Len())
+	assert.Equal(t, int64(10), c.Stats().Cost)
+}
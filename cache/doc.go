@@ -3,15 +3,32 @@
 //   - retrieve data by key
 //   - delete data by key
 //
-// The cache has no memory limit. All items will persist forever.
+// The cache holds a maximum number of items, and each item expires after a TTL.
+// The capacity can also be expressed as a total cost of the items, with WithMaxCost.
+// The TTL can be overridden per item with UpsertWithTTL, and NoExpiration keeps an item forever.
+// With WithSlidingTTL, reading an item extends its expiration.
+// With WithStaleWhileRevalidate, expired items are still served for a while, as they are refreshed in the background.
+// Time is read from a Clock, which can be replaced with WithClock, e.g. by the fake clock of the cachetest package.
+// When the cache is full, an item is evicted according to the chosen Policy:
+// FIFO (default), LRU or LFU. The policy is set with WithPolicy.
+// Expired items are removed when read, or periodically by a janitor set with WithJanitor.
+// All, Keys and Len inspect the whole cache, while UpsertMany, DeleteMany and Clear manage it in bulk.
+// The content of a cache can be written with Save and restored with Load, e.g. across restarts.
+// A Tiered storage puts a cache in front of a slower Store, such as the FileStore.
+// WithOnEvict registers a hook to be notified of every item leaving the cache.
 // The cache stores copies of user values, but it can be used with references.
 // The most common syntax for using the cache is:
 //
-//	c := cache.New[K,V]()
+//	c := cache.New[K, V](maxSize, ttl)
 //	...
 //	v, found := c.Read(key)
 //	if !found {
 //	  v = ...
 //	  c.Upsert(k, v)
 //	}
+//
+// The same can be achieved with GetOrLoad, which also prevents concurrent misses
+// from computing the same value several times:
+//
+//	v, err := c.GetOrLoad(key, func(k K) (V, error) { return ... })
 package cache
@@ -0,0 +1,12 @@
+package cache
+
+// Error is used to define sentinel errors.
+type Error string
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrLoaderPanicked is returned by GetOrLoad to the callers waiting on a loader that panicked.
+const ErrLoaderPanicked = Error("cache loader panicked")
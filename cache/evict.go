@@ -0,0 +1,60 @@
+package cache
+
+// EvictionReason tells why an entry left the cache.
+type EvictionReason byte
+
+const (
+	// EvictionReasonCapacity is used when an entry is discarded to make room for a new one,
+	// or when a value is rejected because it costs more than the whole budget of the cache.
+	EvictionReasonCapacity EvictionReason = iota
+	// EvictionReasonExpired is used when an entry is removed because its TTL is over.
+	EvictionReasonExpired
+	// EvictionReasonDeleted is used when an entry is removed by a call to Delete.
+	EvictionReasonDeleted
+	// EvictionReasonReplaced is used when the value of an entry is overwritten by a new one.
+	EvictionReasonReplaced
+)
+
+// String implements the fmt.Stringer interface.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonCapacity:
+		return "capacity"
+	case EvictionReasonExpired:
+		return "expired"
+	case EvictionReasonDeleted:
+		return "deleted"
+	case EvictionReasonReplaced:
+		return "replaced"
+	default:
+		return ""
+	}
+}
+
+// eviction is an entry that left the cache, waiting to be notified to the OnEvict hook.
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// notifyEviction records an entry that left the cache. The caller must hold the lock.
+func (c *Cache[K, V]) notifyEviction(key K, value V, reason EvictionReason) {
+	if c.onEvict == nil {
+		return
+	}
+
+	c.evictions = append(c.evictions, eviction[K, V]{key: key, value: value, reason: reason})
+}
+
+// unlock releases the lock, then calls the OnEvict hook for the entries that left the cache meanwhile.
+// Calling the hook outside the lock lets it use the cache.
+func (c *Cache[K, V]) unlock() {
+	evictions := c.evictions
+	c.evictions = nil
+	c.mu.Unlock()
+
+	for _, e := range evictions {
+		c.onEvict(e.key, e.value, e.reason)
+	}
+}
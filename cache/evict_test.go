@@ -0,0 +1,123 @@
+package cache_test
+
+import (
+	"goprojects/cache"
+	"goprojects/cache/cachetest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// evicted is a record of a call to the OnEvict hook.
+type evicted struct {
+	key    int
+	value  string
+	reason cache.EvictionReason
+}
+
+func TestCache_OnEvict(t *testing.T) {
+	t.Parallel()
+
+	var got []evicted
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[int, string](2, time.Minute,
+		cache.WithOnEvict(func(key int, value string, reason cache.EvictionReason) {
+			got = append(got, evicted{key: key, value: value, reason: reason})
+		}),
+		cache.WithClock[int, string](clock),
+	)
+
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+	c.Upsert(1, "one")
+	c.Upsert(3, "drei")
+	c.Delete(1)
+	c.Delete(42)
+	c.UpsertWithTTL(4, "vier", time.Millisecond)
+
+	clock.Advance(10 * time.Millisecond)
+	_, _ = c.Read(4)
+
+	expected := []evicted{
+		{key: 1, value: "eins", reason: cache.EvictionReasonReplaced},
+		{key: 2, value: "zwei", reason: cache.EvictionReasonCapacity},
+		{key: 1, value: "one", reason: cache.EvictionReasonDeleted},
+		{key: 4, value: "vier", reason: cache.EvictionReasonExpired},
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestCache_OnEvict_reentrant(t *testing.T) {
+	t.Parallel()
+
+	var c *cache.Cache[int, string]
+	c = cache.New[int, string](1, time.Minute, cache.WithOnEvict(func(key int, value string, reason cache.EvictionReason) {
+		// The hook is called without the lock, so it can use the cache.
+		_, _ = c.Read(key)
+	}))
+
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+
+	got, found := c.Read(2)
+	assert.True(t, found)
+	assert.Equal(t, "zwei", got)
+}
+
+func TestCache_OnEvict_growingEntryIsNotItsOwnVictim(t *testing.T) {
+	t.Parallel()
+
+	var got []evicted
+	c := cache.New[int, string](0, time.Minute,
+		cache.WithPolicy[int, string](cache.LFU),
+		cache.WithMaxCost[int, string](10),
+		cache.WithSizer[int, string](func(v string) int64 { return int64(len(v)) }),
+		cache.WithOnEvict(func(key int, value string, reason cache.EvictionReason) {
+			got = append(got, evicted{key: key, value: value, reason: reason})
+		}),
+	)
+
+	c.Upsert(1, "eins")
+	c.Upsert(2, "zwei")
+	for range 3 {
+		_, _ = c.Read(1)
+	}
+
+	// Key 2 is the least frequently used, but it is the one being written: key 1 makes room for it.
+	c.Upsert(2, "zwei zwei")
+
+	expected := []evicted{
+		{key: 2, value: "zwei", reason: cache.EvictionReasonReplaced},
+		{key: 1, value: "eins", reason: cache.EvictionReasonCapacity},
+	}
+	assert.Equal(t, expected, got)
+
+	value, found := c.Read(2)
+	assert.True(t, found)
+	assert.Equal(t, "zwei zwei", value)
+}
+
+func TestCache_OnEvict_rejectedValue(t *testing.T) {
+	t.Parallel()
+
+	var got []evicted
+	c := cache.New[int, string](0, time.Minute,
+		cache.WithMaxCost[int, string](5),
+		cache.WithSizer[int, string](func(v string) int64 { return int64(len(v)) }),
+		cache.WithOnEvict(func(key int, value string, reason cache.EvictionReason) {
+			got = append(got, evicted{key: key, value: value, reason: reason})
+		}),
+	)
+
+	c.Upsert(1, "eins")
+	c.Upsert(1, "one too many")
+	c.Upsert(2, "zwei too")
+
+	expected := []evicted{
+		{key: 1, value: "eins", reason: cache.EvictionReasonCapacity},
+		{key: 1, value: "one too many", reason: cache.EvictionReasonCapacity},
+		{key: 2, value: "zwei too", reason: cache.EvictionReasonCapacity},
+	}
+	assert.Equal(t, expected, got)
+}
@@ -1,6 +1,6 @@
 module goprojects/cache
 
-go 1.22.1
+go 1.24
 
 require (
 	github.com/stretchr/testify v1.9.0
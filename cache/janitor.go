@@ -0,0 +1,58 @@
+package cache
+
+import "time"
+
+// startJanitor launches a goroutine that purges expired entries at every interval, until Close is called.
+func (c *Cache[K, V]) startJanitor(interval time.Duration) {
+	c.stopJanitor = make(chan struct{})
+	c.janitorDone = make(chan struct{})
+
+	go func() {
+		defer close(c.janitorDone)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.purgeExpired()
+			case <-c.stopJanitor:
+				return
+			}
+		}
+	}()
+}
+
+// purgeExpired removes all the expired entries from the cache.
+func (c *Cache[K, V]) purgeExpired() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	for key, v := range c.data {
+		if v.expired(now) && !c.stale(v, now) {
+			c.deleteKeyValue(key, EvictionReasonExpired)
+		}
+	}
+
+	for key, f := range c.failures {
+		if f.expires.Before(now) {
+			delete(c.failures, key)
+		}
+	}
+}
+
+// Close stops the background janitor, if any, and waits for it to return.
+// It is safe to call Close several times, or on a cache without a janitor.
+// The cache remains usable after Close, but expired entries are only removed when read.
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		if c.stopJanitor == nil {
+			return
+		}
+
+		close(c.stopJanitor)
+		<-c.janitorDone
+	})
+}
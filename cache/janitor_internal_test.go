@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"goprojects/cache/cachetest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_janitor(t *testing.T) {
+	clock := cachetest.NewClock(time.Now())
+	c := New[int, int](3, time.Minute, WithJanitor[int, int](5*time.Millisecond), WithClock[int, int](clock))
+	defer c.Close()
+
+	c.Upsert(1, 10)
+	c.Upsert(2, 20)
+
+	clock.Advance(2 * time.Minute)
+
+	// Wait for the janitor to run, without relying on the timing of its ticks.
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		return len(c.data) == 0
+	}, time.Second, time.Millisecond, "Expired entries should have been purged")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.policy.victim(); found {
+		t.Error("Expired keys should have been removed from the eviction policy")
+	}
+}
+
+func TestCache_Close(t *testing.T) {
+	c := New[int, int](3, time.Minute, WithJanitor[int, int](time.Millisecond))
+
+	c.Close()
+
+	select {
+	case <-c.janitorDone:
+	default:
+		t.Error("Janitor should have stopped")
+	}
+
+	// Closing twice, or closing a cache without a janitor, must not panic.
+	c.Close()
+	New[int, int](3, time.Minute).Close()
+}
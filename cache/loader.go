@@ -0,0 +1,96 @@
+package cache
+
+import "time"
+
+// load is a call to a loader, shared by all the callers of GetOrLoad for the same key.
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// failure is an error returned by a loader, cached until it expires.
+type failure struct {
+	err     error
+	expires time.Time
+}
+
+// GetOrLoad returns the associated value for a key.
+// If the key is absent, the loader is called to compute the value, which is then stored in the cache.
+// Concurrent calls for the same key share a single call to the loader.
+// If the loader returns an error, nothing is stored, unless an error TTL was set with WithErrorTTL.
+// If the key is written, deleted or cleared while the loader runs, the result is returned to the callers,
+// but isn't stored: the cache keeps the latest write.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(K) (V, error)) (V, error) {
+	c.mu.Lock()
+
+	if v, found, _ := c.read(key); found {
+		c.unlock()
+		return v, nil
+	}
+
+	if f, found := c.failures[key]; found {
+		if f.expires.After(c.now()) {
+			c.unlock()
+			var zeroV V
+			return zeroV, f.err
+		}
+		delete(c.failures, key)
+	}
+
+	if l, inFlight := c.loads[key]; inFlight {
+		c.unlock()
+		<-l.done
+		return l.value, l.err
+	}
+
+	l := c.startLoad(key)
+	c.unlock()
+
+	c.runLoad(key, l, loader)
+
+	return l.value, l.err
+}
+
+// startLoad registers a load for a key, so that other callers wait for it. The caller must hold the lock.
+func (c *Cache[K, V]) startLoad(key K) *load[V] {
+	l := &load[V]{done: make(chan struct{}), err: ErrLoaderPanicked}
+	c.loads[key] = l
+
+	return l
+}
+
+// runLoad calls the loader, then completes the load, even if the loader panics.
+func (c *Cache[K, V]) runLoad(key K, l *load[V], loader func(K) (V, error)) {
+	defer c.completeLoad(key, l)
+
+	l.value, l.err = loader(key)
+}
+
+// completeLoad stores the result of a load, unless it was invalidated, and releases the callers waiting for it.
+func (c *Cache[K, V]) completeLoad(key K, l *load[V]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	close(l.done)
+
+	if c.loads[key] != l {
+		// The key was written or deleted during the load: its result is outdated.
+		return
+	}
+
+	delete(c.loads, key)
+
+	switch {
+	case l.err == nil:
+		c.upsert(key, l.value, c.ttl, c.cost(l.value))
+	case c.errorTTL > 0:
+		c.failures[key] = failure{err: l.err, expires: c.now().Add(c.errorTTL)}
+	}
+}
+
+// invalidateLoad forgets the load running for a key, if any, so that its result isn't stored,
+// and the next call to GetOrLoad starts a new one. The caller must hold the lock.
+func (c *Cache[K, V]) invalidateLoad(key K) {
+	delete(c.loads, key)
+}
@@ -0,0 +1,186 @@
+package cache_test
+
+import (
+	"errors"
+	"goprojects/cache"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+	c.Upsert(1, "eins")
+
+	calls := 0
+	loader := func(key int) (string, error) {
+		calls++
+		return "zwei", nil
+	}
+
+	got, err := c.GetOrLoad(1, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "eins", got)
+	assert.Equal(t, 0, calls)
+
+	got, err = c.GetOrLoad(2, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "zwei", got)
+	assert.Equal(t, 1, calls)
+
+	// The loaded value is now in the cache.
+	got, found := c.Read(2)
+	assert.True(t, found)
+	assert.Equal(t, "zwei", got)
+}
+
+func TestCache_GetOrLoad_concurrent(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(key int) (string, error) {
+		calls.Add(1)
+		<-release
+		return "drei", nil
+	}
+
+	const parallelTasks = 10
+	wg := sync.WaitGroup{}
+	wg.Add(parallelTasks)
+
+	for i := 0; i < parallelTasks; i++ {
+		go func() {
+			defer wg.Done()
+			got, err := c.GetOrLoad(3, loader)
+			assert.NoError(t, err)
+			assert.Equal(t, "drei", got)
+		}()
+	}
+
+	// Give the goroutines time to pile up on the same load.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_GetOrLoad_errors(t *testing.T) {
+	t.Parallel()
+
+	errBackend := errors.New("backend unavailable")
+
+	tt := map[string]struct {
+		opts          []cache.Option[int, string]
+		expectedCalls int
+	}{
+		"errors are not cached by default": {
+			expectedCalls: 2,
+		},
+		"errors are cached with an error TTL": {
+			opts:          []cache.Option[int, string]{cache.WithErrorTTL[int, string](time.Minute)},
+			expectedCalls: 1,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			c := cache.New[int, string](5, time.Minute, tc.opts...)
+
+			calls := 0
+			loader := func(key int) (string, error) {
+				calls++
+				return "", errBackend
+			}
+
+			_, err := c.GetOrLoad(4, loader)
+			assert.ErrorIs(t, err, errBackend)
+
+			_, err = c.GetOrLoad(4, loader)
+			assert.ErrorIs(t, err, errBackend)
+
+			assert.Equal(t, tc.expectedCalls, calls)
+
+			_, found := c.Read(4)
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestCache_GetOrLoad_panic(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[int, string](5, time.Minute)
+
+	assert.Panics(t, func() {
+		_, _ = c.GetOrLoad(5, func(int) (string, error) { panic("oops") })
+	})
+
+	// The failed load must not block the next callers.
+	got, err := c.GetOrLoad(5, func(int) (string, error) { return "fünf", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "fünf", got)
+}
+
+func TestCache_GetOrLoad_invalidated(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		invalidate    func(c *cache.Cache[int, string])
+		expected      string
+		expectedFound bool
+	}{
+		"delete": {
+			invalidate: func(c *cache.Cache[int, string]) { c.Delete(6) },
+		},
+		"delete many": {
+			invalidate: func(c *cache.Cache[int, string]) { c.DeleteMany(6) },
+		},
+		"clear": {
+			invalidate: func(c *cache.Cache[int, string]) { c.Clear() },
+		},
+		"upsert": {
+			invalidate:    func(c *cache.Cache[int, string]) { c.Upsert(6, "sechs") },
+			expected:      "sechs",
+			expectedFound: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			c := cache.New[int, string](5, time.Minute)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			loaded := make(chan string)
+
+			go func() {
+				got, _ := c.GetOrLoad(6, func(int) (string, error) {
+					close(started)
+					<-release
+					return "outdated", nil
+				})
+				loaded <- got
+			}()
+
+			<-started
+			tc.invalidate(c)
+			close(release)
+
+			// The caller still gets the result of its load, but the cache keeps the latest write.
+			assert.Equal(t, "outdated", <-loaded)
+
+			got, found := c.Read(6)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
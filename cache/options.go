@@ -0,0 +1,92 @@
+package cache
+
+import "time"
+
+// Option defines a functional option to the cache.
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithPolicy sets the eviction policy used when the cache is full. Default is FIFO.
+func WithPolicy[K comparable, V any](p Policy) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.policy = newPolicy[K](p, c.maxSize)
+	}
+}
+
+// WithJanitor starts a background goroutine that purges expired entries at every interval.
+// The goroutine runs until Close is called. By default, there is no janitor, and
+// expired entries are only removed when they are read.
+func WithJanitor[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.janitorInterval = interval
+	}
+}
+
+// WithErrorTTL caches the errors returned by the loader of GetOrLoad for the given duration.
+// During that time, GetOrLoad returns the cached error instead of calling the loader again.
+// By default, errors aren't cached.
+func WithErrorTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.errorTTL = ttl
+	}
+}
+
+// WithOnEvict registers a hook called every time an entry leaves the cache, with the reason why.
+// Use it to release the resources held by the values.
+// The hook is called after the cache is unlocked, by the goroutine that triggered the eviction.
+func WithOnEvict[K comparable, V any](onEvict func(key K, value V, reason EvictionReason)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = onEvict
+	}
+}
+
+// WithMaxCost sets a budget for the total cost of the entries. When an entry would exceed the budget,
+// as many entries as needed are evicted. An entry costing more than the budget is never stored.
+// The cost of an entry is given to UpsertWithCost, or computed by the sizer set with WithSizer.
+// Costs lower than 1, which would let entries take no room, count as 1.
+// By default, there is no budget, and every entry costs 1.
+func WithMaxCost[K comparable, V any](maxCost int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxCost = maxCost
+	}
+}
+
+// WithSizer sets the function computing the cost of the values written with Upsert, UpsertWithTTL and GetOrLoad.
+// Costs lower than 1 count as 1. By default, every entry costs 1.
+func WithSizer[K comparable, V any](sizer func(V) int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.sizer = sizer
+	}
+}
+
+// WithSlidingTTL makes entries expire after a period of inactivity instead of a fixed time after their writing:
+// every successful read extends the expiration of the entry by its TTL.
+// If maxLifetime is positive, an entry still expires at the latest maxLifetime after its writing.
+// Entries that never expire are not affected.
+func WithSlidingTTL[K comparable, V any](maxLifetime time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.sliding = true
+		c.maxLifetime = maxLifetime
+	}
+}
+
+// WithStaleWhileRevalidate keeps serving expired entries for the duration of the grace window,
+// while the loader refreshes them in the background. Only one refresh runs at a time for a key,
+// shared with GetOrLoad. When the refresh succeeds, the new value replaces the stale one. When it fails,
+// the stale value is served until the end of the grace window, and the next read retries.
+// A refresh whose key is written or deleted meanwhile is discarded.
+// Use Lookup to know whether a value is stale.
+func WithStaleWhileRevalidate[K comparable, V any](grace time.Duration, loader func(K) (V, error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.grace = grace
+		c.staleLoader = loader
+	}
+}
+
+// WithClock sets the clock used to compute expirations. Default is the system clock.
+// It is mostly useful in tests, with the fake clock of the cachetest package.
+// The janitor set with WithJanitor still runs at intervals of real time.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.clock = clock
+	}
+}
@@ -0,0 +1,278 @@
+package cache
+
+import "container/list"
+
+// Policy defines which entry is discarded when the cache is full.
+type Policy byte
+
+const (
+	// FIFO discards the entry that was written the longest time ago. This is the default policy.
+	FIFO Policy = iota
+	// LRU discards the entry that was read or written the longest time ago.
+	LRU
+	// LFU discards the entry that was read or written the least often.
+	// Ties are broken by discarding the least recently used entry.
+	LFU
+)
+
+// String implements the fmt.Stringer interface.
+func (p Policy) String() string {
+	switch p {
+	case FIFO:
+		return "FIFO"
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	default:
+		return ""
+	}
+}
+
+// evictionPolicy keeps track of the keys of the cache, and decides which one should be evicted first.
+// All operations run in constant time.
+type evictionPolicy[K comparable] interface {
+	// add registers a new key.
+	add(key K)
+	// update registers a write on an existing key.
+	update(key K)
+	// access registers a read on an existing key.
+	access(key K)
+	// remove forgets a key.
+	remove(key K)
+	// victim returns the next key to be evicted, and false if there is none.
+	victim() (K, bool)
+	// ranking returns all the keys, from the next to be evicted to the last one.
+	ranking() []rankedKey[K]
+	// restore registers a key after all the others, with the given number of uses.
+	// Keys must be restored in the order of the ranking.
+	restore(key K, uses int)
+}
+
+// rankedKey is a key with the number of times it was used, as tracked by the policy.
+type rankedKey[K comparable] struct {
+	key  K
+	uses int
+}
+
+// newPolicy returns the eviction policy matching p. Unknown policies default to FIFO.
+func newPolicy[K comparable](p Policy, capacity int) evictionPolicy[K] {
+	switch p {
+	case LRU:
+		return &lruPolicy[K]{orderedKeys: newOrderedKeys[K](capacity)}
+	case LFU:
+		return &lfuPolicy[K]{
+			frequencies: list.New(),
+			items:       make(map[K]lfuItem, capacity),
+		}
+	default:
+		return &fifoPolicy[K]{orderedKeys: newOrderedKeys[K](capacity)}
+	}
+}
+
+// orderedKeys is a list of keys, indexed by key for constant time access.
+type orderedKeys[K comparable] struct {
+	keys     *list.List
+	elements map[K]*list.Element
+}
+
+func newOrderedKeys[K comparable](capacity int) orderedKeys[K] {
+	return orderedKeys[K]{
+		keys:     list.New(),
+		elements: make(map[K]*list.Element, capacity),
+	}
+}
+
+// pushBack appends a key at the end of the list.
+func (o orderedKeys[K]) pushBack(key K) {
+	o.elements[key] = o.keys.PushBack(key)
+}
+
+// moveToBack moves an existing key to the end of the list.
+func (o orderedKeys[K]) moveToBack(key K) {
+	if e, ok := o.elements[key]; ok {
+		o.keys.MoveToBack(e)
+	}
+}
+
+// remove removes a key from the list.
+func (o orderedKeys[K]) remove(key K) {
+	if e, ok := o.elements[key]; ok {
+		o.keys.Remove(e)
+		delete(o.elements, key)
+	}
+}
+
+// front returns the first key of the list, and false if the list is empty.
+func (o orderedKeys[K]) front() (K, bool) {
+	e := o.keys.Front()
+	if e == nil {
+		var zeroK K
+		return zeroK, false
+	}
+
+	return e.Value.(K), true
+}
+
+// ranking returns the keys in the order of the list. Their uses aren't tracked.
+func (o orderedKeys[K]) ranking() []rankedKey[K] {
+	ranking := make([]rankedKey[K], 0, o.keys.Len())
+	for e := o.keys.Front(); e != nil; e = e.Next() {
+		ranking = append(ranking, rankedKey[K]{key: e.Value.(K), uses: 1})
+	}
+
+	return ranking
+}
+
+// restore appends a key at the end of the list.
+func (o orderedKeys[K]) restore(key K, _ int) {
+	o.pushBack(key)
+}
+
+// fifoPolicy evicts keys in the order they were written.
+type fifoPolicy[K comparable] struct {
+	orderedKeys[K]
+}
+
+func (p *fifoPolicy[K]) add(key K) {
+	p.pushBack(key)
+}
+
+// update moves the key to the end of the queue, as if it was newly written.
+func (p *fifoPolicy[K]) update(key K) {
+	p.moveToBack(key)
+}
+
+// access is a no-op: reading doesn't change the order of writes.
+func (p *fifoPolicy[K]) access(K) {}
+
+func (p *fifoPolicy[K]) victim() (K, bool) {
+	return p.front()
+}
+
+// lruPolicy evicts the key that was used the longest time ago.
+type lruPolicy[K comparable] struct {
+	orderedKeys[K]
+}
+
+func (p *lruPolicy[K]) add(key K) {
+	p.pushBack(key)
+}
+
+func (p *lruPolicy[K]) update(key K) {
+	p.access(key)
+}
+
+// access moves the key to the end of the list, making it the most recently used.
+func (p *lruPolicy[K]) access(key K) {
+	p.moveToBack(key)
+}
+
+func (p *lruPolicy[K]) victim() (K, bool) {
+	return p.front()
+}
+
+// lfuPolicy evicts the key that was used the least often.
+// Keys are grouped in buckets of identical frequency, sorted by increasing frequency.
+// Within a bucket, keys are sorted from least to most recently used, to break ties.
+type lfuPolicy[K comparable] struct {
+	// frequencies holds *frequencyBucket values.
+	frequencies *list.List
+	items       map[K]lfuItem
+}
+
+// frequencyBucket holds all the keys that have been used the same number of times.
+type frequencyBucket struct {
+	frequency int
+	keys      *list.List
+}
+
+// lfuItem locates a key in its bucket.
+type lfuItem struct {
+	bucket *list.Element
+	key    *list.Element
+}
+
+func (p *lfuPolicy[K]) add(key K) {
+	front := p.frequencies.Front()
+	if front == nil || front.Value.(*frequencyBucket).frequency != 1 {
+		front = p.frequencies.PushFront(&frequencyBucket{frequency: 1, keys: list.New()})
+	}
+
+	p.items[key] = lfuItem{bucket: front, key: front.Value.(*frequencyBucket).keys.PushBack(key)}
+}
+
+func (p *lfuPolicy[K]) update(key K) {
+	p.access(key)
+}
+
+// access moves the key to the bucket of the next frequency, making it the most recently used of this bucket.
+func (p *lfuPolicy[K]) access(key K) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+
+	current := item.bucket.Value.(*frequencyBucket)
+	next := item.bucket.Next()
+	if next == nil || next.Value.(*frequencyBucket).frequency != current.frequency+1 {
+		next = p.frequencies.InsertAfter(&frequencyBucket{frequency: current.frequency + 1, keys: list.New()}, item.bucket)
+	}
+
+	p.detach(item)
+	p.items[key] = lfuItem{bucket: next, key: next.Value.(*frequencyBucket).keys.PushBack(key)}
+}
+
+func (p *lfuPolicy[K]) remove(key K) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+
+	p.detach(item)
+	delete(p.items, key)
+}
+
+// victim returns the least recently used key among those with the lowest frequency.
+func (p *lfuPolicy[K]) victim() (K, bool) {
+	front := p.frequencies.Front()
+	if front == nil {
+		var zeroK K
+		return zeroK, false
+	}
+
+	return front.Value.(*frequencyBucket).keys.Front().Value.(K), true
+}
+
+// ranking returns the keys bucket by bucket, by increasing frequency.
+func (p *lfuPolicy[K]) ranking() []rankedKey[K] {
+	ranking := make([]rankedKey[K], 0, len(p.items))
+	for b := p.frequencies.Front(); b != nil; b = b.Next() {
+		bucket := b.Value.(*frequencyBucket)
+		for e := bucket.keys.Front(); e != nil; e = e.Next() {
+			ranking = append(ranking, rankedKey[K]{key: e.Value.(K), uses: bucket.frequency})
+		}
+	}
+
+	return ranking
+}
+
+// restore appends a key to the bucket of the given frequency, which must be the last bucket or a new one.
+func (p *lfuPolicy[K]) restore(key K, uses int) {
+	back := p.frequencies.Back()
+	if back == nil || back.Value.(*frequencyBucket).frequency != uses {
+		back = p.frequencies.PushBack(&frequencyBucket{frequency: uses, keys: list.New()})
+	}
+
+	p.items[key] = lfuItem{bucket: back, key: back.Value.(*frequencyBucket).keys.PushBack(key)}
+}
+
+// detach removes an item from its bucket, and drops the bucket if it is now empty.
+func (p *lfuPolicy[K]) detach(item lfuItem) {
+	bucket := item.bucket.Value.(*frequencyBucket)
+	bucket.keys.Remove(item.key)
+
+	if bucket.keys.Len() == 0 {
+		p.frequencies.Remove(item.bucket)
+	}
+}
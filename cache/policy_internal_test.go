@@ -0,0 +1,90 @@
+package cache
+
+import "testing"
+
+func TestPolicy_victim(t *testing.T) {
+	tt := map[string]struct {
+		policy   Policy
+		expected int
+	}{
+		"fifo evicts the oldest write": {
+			policy:   FIFO,
+			expected: 1,
+		},
+		"lru evicts the oldest use": {
+			policy:   LRU,
+			expected: 2,
+		},
+		"lfu evicts the least used": {
+			policy:   LFU,
+			expected: 3,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			p := newPolicy[int](tc.policy, 3)
+
+			p.add(1)
+			p.add(2)
+			p.add(3)
+			p.access(2)
+			p.update(2)
+			p.access(3)
+			p.access(1)
+
+			got, found := p.victim()
+			if !found {
+				t.Fatal("a victim should be found")
+			}
+			if got != tc.expected {
+				t.Errorf("expected victim %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPolicy_remove(t *testing.T) {
+	for _, policy := range []Policy{FIFO, LRU, LFU} {
+		p := newPolicy[string](policy, 2)
+
+		p.add("a")
+		p.add("b")
+		p.remove("a")
+
+		got, found := p.victim()
+		if !found || got != "b" {
+			t.Errorf("policy %s: expected victim b, got %q (found: %t)", policy, got, found)
+		}
+
+		p.remove("b")
+		if _, found = p.victim(); found {
+			t.Errorf("policy %s: expected no victim in an empty policy", policy)
+		}
+	}
+}
+
+func TestLFUPolicy_buckets(t *testing.T) {
+	p := newPolicy[string](LFU, 3).(*lfuPolicy[string])
+
+	p.add("a")
+	p.add("b")
+	p.access("a")
+	p.access("a")
+
+	if p.frequencies.Len() != 2 {
+		t.Fatalf("expected 2 frequency buckets, got %d", p.frequencies.Len())
+	}
+
+	// Removing b empties the bucket of frequency 1, which must be dropped.
+	p.remove("b")
+
+	if p.frequencies.Len() != 1 {
+		t.Fatalf("expected 1 frequency bucket, got %d", p.frequencies.Len())
+	}
+
+	bucket := p.frequencies.Front().Value.(*frequencyBucket)
+	if bucket.frequency != 3 {
+		t.Errorf("expected remaining bucket to have frequency 3, got %d", bucket.frequency)
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// Sharded is a key-value storage split in independently locked segments,
+// to reduce contention when used by many goroutines.
+// Each key always belongs to the same segment, chosen from a hash of the key.
+type Sharded[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*Cache[K, V]
+}
+
+// NewSharded creates a usable Sharded cache of the given number of segments.
+// The maxSize is spread between the segments, so that they hold at most maxSize entries in total.
+// When maxSize is positive but lower than the number of segments, there are only maxSize segments of one entry.
+// The options apply to each segment: in particular, a budget set with WithMaxCost applies per segment, not in total.
+// Eviction happens per segment: when a segment is full, its own entries are evicted.
+func NewSharded[K comparable, V any](shards int, maxSize int, ttl time.Duration, opts ...Option[K, V]) *Sharded[K, V] {
+	shards = max(shards, 1)
+	if maxSize > 0 {
+		// A segment of size 0 would be unbounded.
+		shards = min(shards, maxSize)
+	}
+
+	s := &Sharded[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*Cache[K, V], shards),
+	}
+
+	for i := range s.shards {
+		// The first maxSize%shards segments hold one more entry than the others.
+		shardSize := maxSize / shards
+		if i < maxSize%shards {
+			shardSize++
+		}
+
+		s.shards[i] = New[K, V](shardSize, ttl, opts...)
+	}
+
+	return s
+}
+
+// Read returns the associated value for a key,
+// and a boolean to false if the key is absent.
+func (s *Sharded[K, V]) Read(key K) (V, bool) {
+	return s.shard(key).Read(key)
+}
+
+// Upsert overwrites the value for a given key. The entry expires after the default TTL of the cache.
+func (s *Sharded[K, V]) Upsert(key K, value V) {
+	s.shard(key).Upsert(key, value)
+}
+
+// UpsertWithTTL overwrites the value for a given key. The entry expires after the given TTL,
+// or never if the TTL is NoExpiration.
+func (s *Sharded[K, V]) UpsertWithTTL(key K, value V, ttl time.Duration) {
+	s.shard(key).UpsertWithTTL(key, value, ttl)
+}
+
+// Delete removes the entry for the given key.
+func (s *Sharded[K, V]) Delete(key K) {
+	s.shard(key).Delete(key)
+}
+
+// GetOrLoad returns the associated value for a key, calling the loader on a miss.
+// See Cache.GetOrLoad.
+func (s *Sharded[K, V]) GetOrLoad(key K, loader func(K) (V, error)) (V, error) {
+	return s.shard(key).GetOrLoad(key, loader)
+}
+
+// Close stops the background janitors of all the segments, if any.
+func (s *Sharded[K, V]) Close() {
+	for _, shard := range s.shards {
+		shard.Close()
+	}
+}
+
+// shard returns the segment responsible for a key.
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[maphash.Comparable(s.seed, key)%uint64(len(s.shards))]
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSharded_evictsPerSegment(t *testing.T) {
+	// Two segments of 2 entries each.
+	s := NewSharded[int, int](2, 4, time.Minute)
+	defer s.Close()
+
+	// Find 3 keys of the first segment, and 2 keys of the second one.
+	var first, second []int
+	for key := 0; len(first) < 3 || len(second) < 2; key++ {
+		switch {
+		case s.shard(key) == s.shards[0] && len(first) < 3:
+			first = append(first, key)
+		case s.shard(key) == s.shards[1] && len(second) < 2:
+			second = append(second, key)
+		}
+	}
+
+	for _, key := range append(second, first...) {
+		s.Upsert(key, key)
+	}
+
+	// The third key of the first segment evicts the oldest one of this segment only.
+	if _, found := s.Read(first[0]); found {
+		t.Errorf("expected key %d to be evicted from its full segment", first[0])
+	}
+
+	for _, key := range append(second, first[1:]...) {
+		if _, found := s.Read(key); !found {
+			t.Errorf("expected key %d to be kept", key)
+		}
+	}
+}
+
+func TestNewSharded_capacity(t *testing.T) {
+	tt := map[string]struct {
+		shards, maxSize int
+		expected        []int
+	}{
+		"even":                 {shards: 4, maxSize: 8, expected: []int{2, 2, 2, 2}},
+		"remainder":            {shards: 4, maxSize: 10, expected: []int{3, 3, 2, 2}},
+		"more shards than max": {shards: 16, maxSize: 10, expected: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		"unbounded":            {shards: 2, maxSize: 0, expected: []int{0, 0}},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			s := NewSharded[int, int](tc.shards, tc.maxSize, time.Minute)
+			defer s.Close()
+
+			got := make([]int, len(s.shards))
+			for i, shard := range s.shards {
+				got[i] = shard.maxSize
+			}
+
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("expected segment sizes %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cache_test
+
+import (
+	"fmt"
+	"goprojects/cache"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharded(t *testing.T) {
+	// Keys aren't spread evenly between segments: leave room for all of them in any segment.
+	c := cache.NewSharded[int, string](4, 40, time.Minute)
+	defer c.Close()
+
+	for i := 0; i < 10; i++ {
+		c.Upsert(i, fmt.Sprint(i))
+	}
+
+	for i := 0; i < 10; i++ {
+		v, found := c.Read(i)
+		assert.True(t, found)
+		assert.Equal(t, fmt.Sprint(i), v)
+	}
+
+	c.Upsert(5, "fünf")
+	v, found := c.Read(5)
+	assert.True(t, found)
+	assert.Equal(t, "fünf", v)
+
+	c.Delete(5)
+	v, found = c.Read(5)
+	assert.False(t, found)
+	assert.Equal(t, "", v)
+}
+
+func TestSharded_Parallel_goroutines(t *testing.T) {
+	c := cache.NewSharded[int, int](8, 100, time.Minute)
+
+	const parallelTasks = 10
+	wg := sync.WaitGroup{}
+	wg.Add(parallelTasks)
+
+	for i := 0; i < parallelTasks; i++ {
+		go func(j int) {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				c.Upsert(k, j)
+				_, _ = c.Read(k)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// readMostly is the API shared by Cache and Sharded that the parallel benchmarks use.
+type readMostly interface {
+	Read(key int) (int, bool)
+	Upsert(key int, value int)
+}
+
+// benchmarkParallel runs a read-heavy workload on the cache from all the available goroutines.
+func benchmarkParallel(b *testing.B, c readMostly) {
+	const keys = 10_000
+	for i := 0; i < keys; i++ {
+		c.Upsert(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			key := rand.IntN(keys)
+			if i%10 == 0 {
+				c.Upsert(key, i)
+			} else {
+				_, _ = c.Read(key)
+			}
+		}
+	})
+}
+
+func BenchmarkCache_Parallel(b *testing.B) {
+	benchmarkParallel(b, cache.New[int, int](10_000, time.Hour))
+}
+
+func BenchmarkSharded_Parallel(b *testing.B) {
+	for _, shards := range []int{4, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			benchmarkParallel(b, cache.NewSharded[int, int](shards, 10_000, time.Hour))
+		})
+	}
+}
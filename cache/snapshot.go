@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// snapshot is the content of a cache, as saved by Save.
+// Fields are exported for the gob encoder.
+type snapshot[K comparable, V any] struct {
+	// Entries are sorted from the next to be evicted to the last one.
+	Entries []snapshotEntry[K, V]
+}
+
+// snapshotEntry is an entry of the cache, with everything needed to restore its expiration and eviction order.
+type snapshotEntry[K comparable, V any] struct {
+	Key     K
+	Value   V
+	Expires time.Time
+	TTL     time.Duration
+	Created time.Time
+	Cost    int64
+	Uses    int
+}
+
+// entry returns the entry of the cache saved in e.
+func (e snapshotEntry[K, V]) entry() entryWithTimeout[V] {
+	return entryWithTimeout[V]{value: e.Value, expires: e.Expires, cost: max(e.Cost, 1), ttl: e.TTL, created: e.Created}
+}
+
+// Save writes the content of the cache to w, using encoding/gob.
+// Expiration times and eviction order are preserved, so that a cache restored with Load
+// behaves as this one. Keys and values must be encodable by encoding/gob.
+func (c *Cache[K, V]) Save(w io.Writer) error {
+	c.mu.Lock()
+
+	ranking := c.policy.ranking()
+	s := snapshot[K, V]{Entries: make([]snapshotEntry[K, V], 0, len(ranking))}
+	for _, rk := range ranking {
+		e := c.data[rk.key]
+		s.Entries = append(s.Entries, snapshotEntry[K, V]{Key: rk.key, Value: e.value, Expires: e.expires, TTL: e.ttl, Created: e.created, Cost: e.cost, Uses: rk.uses})
+	}
+
+	c.unlock()
+
+	err := gob.NewEncoder(w).Encode(s)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache: %w", err)
+	}
+
+	return nil
+}
+
+// Load replaces the content of the cache with the one read from r, as written by Save.
+// Entries that have expired meanwhile are skipped. If the snapshot holds more entries, or more cost,
+// than the cache can, the ones that would be evicted first are skipped.
+// The entries already present in the cache are discarded, with the reason EvictionReasonDeleted.
+func (c *Cache[K, V]) Load(r io.Reader) error {
+	var s snapshot[K, V]
+
+	err := gob.NewDecoder(r).Decode(&s)
+	if err != nil {
+		return fmt.Errorf("unable to decode cache: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.clear()
+
+	now := c.now()
+	// Expired entries don't take room: skip them before keeping the last maxSize entries.
+	entries := slices.DeleteFunc(s.Entries, func(e snapshotEntry[K, V]) bool {
+		return e.entry().expired(now)
+	})
+	if c.maxSize > 0 {
+		entries = entries[max(0, len(entries)-c.maxSize):]
+	}
+
+	for _, e := range entries {
+		restored := e.entry()
+		c.data[e.Key] = restored
+		c.totalCost += restored.cost
+		c.policy.restore(e.Key, e.Uses)
+	}
+
+	// The budget may be lower than when the snapshot was taken.
+	c.evict(0, 0)
+
+	return nil
+}
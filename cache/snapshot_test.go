@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"bytes"
+	"goprojects/cache"
+	"goprojects/cache/cachetest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []cache.Policy{cache.FIFO, cache.LRU, cache.LFU} {
+		t.Run(policy.String(), func(t *testing.T) {
+			saved := cache.New[int, string](3, time.Minute, cache.WithPolicy[int, string](policy))
+			saved.Upsert(1, "eins")
+			saved.Upsert(2, "zwei")
+			saved.Upsert(3, "drei")
+			saved.UpsertWithTTL(4, "vier", cache.NoExpiration)
+			_, _ = saved.Read(2)
+			_, _ = saved.Read(3)
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, saved.Save(buf))
+
+			restored := cache.New[int, string](3, time.Minute, cache.WithPolicy[int, string](policy))
+			restored.Upsert(5, "fünf")
+			require.NoError(t, restored.Load(buf))
+
+			// Previous content is discarded.
+			_, found := restored.Read(5)
+			assert.False(t, found)
+
+			// Both caches must evict the same entry on the next insertion.
+			saved.Upsert(6, "sechs")
+			restored.Upsert(6, "sechs")
+
+			for key := 1; key <= 4; key++ {
+				expected, expectedFound := saved.Read(key)
+				got, found := restored.Read(key)
+				assert.Equal(t, expectedFound, found, "key %d", key)
+				assert.Equal(t, expected, got, "key %d", key)
+			}
+		})
+	}
+}
+
+func TestCache_Load_expired(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	saved := cache.New[string, string](5, time.Minute, cache.WithClock[string, string](clock))
+	saved.UpsertWithTTL("Norwegian", "Blue", time.Millisecond)
+	saved.Upsert("Parrot", "Green")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, saved.Save(buf))
+
+	clock.Advance(10 * time.Millisecond)
+
+	restored := cache.New[string, string](5, time.Minute, cache.WithClock[string, string](clock))
+	require.NoError(t, restored.Load(buf))
+
+	_, found := restored.Read("Norwegian")
+	assert.False(t, found)
+
+	got, found := restored.Read("Parrot")
+	assert.True(t, found)
+	assert.Equal(t, "Green", got)
+}
+
+func TestCache_Load_expiredDontTakeRoom(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	saved := cache.New[string, string](3, time.Minute, cache.WithClock[string, string](clock))
+	saved.Upsert("Norwegian", "Blue")
+	saved.Upsert("Parrot", "Green")
+	saved.UpsertWithTTL("Dead", "Parrot", time.Millisecond)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, saved.Save(buf))
+
+	clock.Advance(10 * time.Millisecond)
+
+	// The expired entry is skipped before the snapshot is cut down to the size of the cache.
+	restored := cache.New[string, string](2, time.Minute, cache.WithClock[string, string](clock))
+	require.NoError(t, restored.Load(buf))
+
+	for _, key := range []string{"Norwegian", "Parrot"} {
+		_, found := restored.Read(key)
+		assert.True(t, found, "key %s", key)
+	}
+}
+
+func TestCache_Load_invalid(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New[string, string](5, time.Minute)
+	err := c.Load(strings.NewReader("not a gob"))
+	assert.Error(t, err)
+}
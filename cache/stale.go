@@ -0,0 +1,35 @@
+package cache
+
+import "time"
+
+// Lookup returns the associated value for a key, a boolean to false if the key is absent,
+// and a boolean to true if the value has expired, but is still served while it is refreshed.
+// See WithStaleWhileRevalidate.
+func (c *Cache[K, V]) Lookup(key K) (value V, found bool, stale bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.read(key)
+}
+
+// stale returns whether an expired entry can still be served, because it is within the grace window.
+func (c *Cache[K, V]) stale(e entryWithTimeout[V], now time.Time) bool {
+	return c.staleLoader != nil && !e.expired(now.Add(-c.grace))
+}
+
+// revalidate refreshes the value of a key in the background, unless a load is already running for it.
+// The caller must hold the lock.
+func (c *Cache[K, V]) revalidate(key K) {
+	if _, inFlight := c.loads[key]; inFlight {
+		return
+	}
+
+	l := c.startLoad(key)
+
+	go func() {
+		// A panicking loader must not crash the program: the load fails with ErrLoaderPanicked.
+		defer func() { _ = recover() }()
+
+		c.runLoad(key, l, c.staleLoader)
+	}()
+}
@@ -0,0 +1,132 @@
+package cache_test
+
+import (
+	"errors"
+	"goprojects/cache"
+	"goprojects/cache/cachetest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "fresh", nil
+	}
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Minute,
+		cache.WithStaleWhileRevalidate(time.Minute, loader),
+		cache.WithClock[string, string](clock),
+	)
+	c.Upsert("rate", "old")
+
+	got, found, stale := c.Lookup("rate")
+	assert.True(t, found)
+	assert.False(t, stale)
+	assert.Equal(t, "old", got)
+
+	clock.Advance(90 * time.Second)
+
+	// Expired but within the grace window: the stale value is served, and refreshed once.
+	for i := 0; i < 3; i++ {
+		got, found, stale = c.Lookup("rate")
+		assert.True(t, found)
+		assert.True(t, stale)
+		assert.Equal(t, "old", got)
+	}
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		got, _, stale := c.Lookup("rate")
+		return got == "fresh" && !stale
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_StaleWhileRevalidate_graceOver(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Minute,
+		cache.WithStaleWhileRevalidate(time.Minute, func(string) (string, error) {
+			return "", errors.New("should not be called")
+		}),
+		cache.WithClock[string, string](clock),
+	)
+	c.Upsert("rate", "old")
+
+	clock.Advance(3 * time.Minute)
+
+	_, found := c.Read("rate")
+	assert.False(t, found)
+}
+
+func TestCache_StaleWhileRevalidate_failure(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{}, 1)
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Minute,
+		cache.WithStaleWhileRevalidate(time.Minute, func(string) (string, error) {
+			defer func() { done <- struct{}{} }()
+			return "", errors.New("backend unavailable")
+		}),
+		cache.WithClock[string, string](clock),
+	)
+	c.Upsert("rate", "old")
+
+	clock.Advance(90 * time.Second)
+
+	_, _, _ = c.Lookup("rate")
+	<-done
+
+	// The failed refresh keeps the stale value.
+	assert.Eventually(t, func() bool {
+		got, found, stale := c.Lookup("rate")
+		return found && stale && got == "old"
+	}, time.Second, time.Millisecond)
+}
+
+func TestCache_StaleWhileRevalidate_deleted(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(key string) (string, error) {
+		close(started)
+		<-release
+		return "fresh", nil
+	}
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[string, string](5, time.Minute,
+		cache.WithStaleWhileRevalidate(time.Minute, loader),
+		cache.WithClock[string, string](clock),
+	)
+	c.Upsert("rate", "old")
+	clock.Advance(90 * time.Second)
+
+	_, _, stale := c.Lookup("rate")
+	assert.True(t, stale)
+
+	// Deleting the key during the refresh must not be reverted when the refresh completes.
+	<-started
+	c.Delete("rate")
+	close(release)
+
+	assert.Never(t, func() bool {
+		_, found := c.Read("rate")
+		return found
+	}, 100*time.Millisecond, time.Millisecond)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import "sync/atomic"
+
+// Stats holds the counters of a cache, since its creation.
+type Stats struct {
+	// Hits is the number of reads that found a value.
+	Hits uint64
+	// Misses is the number of reads that found no value, including expired ones.
+	Misses uint64
+	// Expirations is the number of entries removed because their TTL was over.
+	Expirations uint64
+	// Evictions is the number of entries discarded to make room for new ones.
+	Evictions uint64
+	// Size is the current number of entries, including the expired ones that haven't been removed yet.
+	Size int
+	// Cost is the current total cost of the entries.
+	Cost int64
+}
+
+// counters holds the statistics of a cache, updated atomically.
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	expirations atomic.Uint64
+	evictions   atomic.Uint64
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	size, cost := len(c.data), c.totalCost
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:        c.counters.hits.Load(),
+		Misses:      c.counters.misses.Load(),
+		Expirations: c.counters.expirations.Load(),
+		Evictions:   c.counters.evictions.Load(),
+		Size:        size,
+		Cost:        cost,
+	}
+}
+
+// Stats returns the sum of the counters of all the segments.
+func (s *Sharded[K, V]) Stats() Stats {
+	var total Stats
+	for _, shard := range s.shards {
+		stats := shard.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Expirations += stats.Expirations
+		total.Evictions += stats.Evictions
+		total.Size += stats.Size
+		total.Cost += stats.Cost
+	}
+
+	return total
+}
@@ -0,0 +1,54 @@
+package cache_test
+
+import (
+	"goprojects/cache"
+	"goprojects/cache/cachetest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Stats(t *testing.T) {
+	t.Parallel()
+
+	clock := cachetest.NewClock(time.Now())
+	c := cache.New[int, int](2, time.Minute, cache.WithClock[int, int](clock))
+
+	c.Upsert(1, 1)
+	c.Upsert(2, 2)
+	c.Upsert(3, 3) // evicts 1
+	c.UpsertWithTTL(2, 2, time.Millisecond)
+
+	clock.Advance(10 * time.Millisecond)
+
+	_, _ = c.Read(1) // miss
+	_, _ = c.Read(2) // expired
+	_, _ = c.Read(3) // hit
+	_, _ = c.Read(3) // hit
+
+	expected := cache.Stats{
+		Hits:        2,
+		Misses:      2,
+		Expirations: 1,
+		Evictions:   1,
+		Size:        1,
+		Cost:        1,
+	}
+	assert.Equal(t, expected, c.Stats())
+}
+
+func TestSharded_Stats(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewSharded[int, int](4, 100, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		c.Upsert(i, i)
+		_, _ = c.Read(i)
+		_, _ = c.Read(-i - 1)
+	}
+
+	expected := cache.Stats{Hits: 10, Misses: 10, Size: 10, Cost: 10}
+	assert.Equal(t, expected, c.Stats())
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Store is a slower storage, used as a second tier behind a Cache by Tiered.
+type Store[K comparable, V any] interface {
+	// Get returns the value for a key, and false if the key is absent.
+	Get(key K) (V, bool, error)
+	// Set overwrites the value for a key.
+	Set(key K, value V) error
+	// Delete removes the value for a key. Deleting an absent key isn't an error.
+	Delete(key K) error
+}
+
+// FileStore is a Store keeping each value in its own file of a directory, encoded with encoding/gob.
+// Keys and values must be encodable by encoding/gob. Values never expire.
+// It is safe for concurrent use.
+type FileStore[K comparable, V any] struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore writing in the given directory, which is created if needed.
+func NewFileStore[K comparable, V any](dir string) (*FileStore[K, V], error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create store directory %s: %w", dir, err)
+	}
+
+	return &FileStore[K, V]{dir: dir}, nil
+}
+
+// Get implements the Store interface.
+func (s *FileStore[K, V]) Get(key K) (V, bool, error) {
+	var value V
+
+	path, err := s.path(key)
+	if err != nil {
+		return value, false, err
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return value, false, nil
+	case err != nil:
+		return value, false, fmt.Errorf("unable to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	err = gob.NewDecoder(f).Decode(&value)
+	if err != nil {
+		return value, false, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+
+	return value, true, nil
+}
+
+// Set implements the Store interface.
+// The value is written to a temporary file first, so that readers never see a partial value.
+func (s *FileStore[K, V]) Set(key K, value V) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	err = gob.NewEncoder(tmp).Encode(value)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("unable to encode value: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %w", tmp.Name(), err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Delete implements the Store interface.
+func (s *FileStore[K, V]) Delete(key K) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to delete %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// path returns the name of the file holding the value of a key, derived from a hash of its encoding.
+func (s *FileStore[K, V]) path(key K) (string, error) {
+	var buf bytes.Buffer
+
+	err := gob.NewEncoder(&buf).Encode(key)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode key %v: %w", key, err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".gob"), nil
+}
@@ -0,0 +1,54 @@
+package cache_test
+
+import (
+	"goprojects/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	s, err := cache.NewFileStore[string, []string](t.TempDir())
+	require.NoError(t, err)
+
+	_, found, err := s.Get("colours")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, s.Set("colours", []string{"red", "green"}))
+	require.NoError(t, s.Set("colours", []string{"blue"}))
+
+	got, found, err := s.Get("colours")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"blue"}, got)
+
+	require.NoError(t, s.Delete("colours"))
+	require.NoError(t, s.Delete("colours"))
+
+	_, found, err = s.Get("colours")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestFileStore_persistent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	written, err := cache.NewFileStore[int, string](dir)
+	require.NoError(t, err)
+	require.NoError(t, written.Set(42, "answer"))
+
+	// Another store on the same directory, e.g. after a restart, finds the value.
+	read, err := cache.NewFileStore[int, string](dir)
+	require.NoError(t, err)
+
+	got, found, err := read.Get(42)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "answer", got)
+}
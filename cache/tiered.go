@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tiered is a two-tier key-value storage: a Cache in front of a slower Store.
+// Reads are served by the cache, and fall through to the store on a miss, populating the cache.
+// Writes go to both tiers, and deletions invalidate both.
+type Tiered[K comparable, V any] struct {
+	l1 *Cache[K, V]
+	l2 Store[K, V]
+}
+
+// NewTiered returns a Tiered storage using l1 as first tier and l2 as second tier.
+func NewTiered[K comparable, V any](l1 *Cache[K, V], l2 Store[K, V]) *Tiered[K, V] {
+	return &Tiered[K, V]{l1: l1, l2: l2}
+}
+
+// errNotInStore is returned by the loader of Read when the key is absent from the second tier.
+const errNotInStore = Error("key not found in second tier")
+
+// Read returns the associated value for a key, and a boolean to false if the key is absent from both tiers.
+// On a miss, the first tier is populated with GetOrLoad: concurrent reads share a single call to the second tier,
+// and a value read while the key is written or deleted isn't stored. If the first tier has an error TTL,
+// the errors of the second tier, and the keys it doesn't have, are remembered until the TTL is over or the key is written.
+func (t *Tiered[K, V]) Read(key K) (V, bool, error) {
+	v, err := t.l1.GetOrLoad(key, func(key K) (V, error) {
+		v, found, err := t.l2.Get(key)
+		switch {
+		case err != nil:
+			return v, fmt.Errorf("unable to read from second tier: %w", err)
+		case !found:
+			return v, errNotInStore
+		}
+
+		return v, nil
+	})
+
+	switch {
+	case errors.Is(err, errNotInStore):
+		return v, false, nil
+	case err != nil:
+		return v, false, err
+	}
+
+	return v, true, nil
+}
+
+// Upsert overwrites the value for a given key in both tiers.
+// If the second tier fails, the first tier is invalidated, so that it doesn't hold a value the store doesn't have.
+func (t *Tiered[K, V]) Upsert(key K, value V) error {
+	err := t.l2.Set(key, value)
+	if err != nil {
+		t.l1.Delete(key)
+		return fmt.Errorf("unable to write to second tier: %w", err)
+	}
+
+	t.l1.Upsert(key, value)
+	return nil
+}
+
+// Delete removes the entry for the given key from both tiers.
+// The first tier is invalidated even if the second tier fails.
+func (t *Tiered[K, V]) Delete(key K) error {
+	err := t.l2.Delete(key)
+	t.l1.Delete(key)
+
+	if err != nil {
+		return fmt.Errorf("unable to delete from second tier: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package cache_test
+
+import (
+	"errors"
+	"goprojects/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiered(t *testing.T) {
+	t.Parallel()
+
+	l2, err := cache.NewFileStore[int, string](t.TempDir())
+	require.NoError(t, err)
+
+	l1 := cache.New[int, string](1, time.Minute)
+	c := cache.NewTiered(l1, l2)
+
+	require.NoError(t, c.Upsert(1, "eins"))
+	require.NoError(t, c.Upsert(2, "zwei"))
+
+	// 1 was evicted from the first tier, but is still in the second one.
+	_, found := l1.Read(1)
+	assert.False(t, found)
+
+	got, found, err := c.Read(1)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "eins", got)
+
+	// Reading through populated the first tier.
+	got, found = l1.Read(1)
+	assert.True(t, found)
+	assert.Equal(t, "eins", got)
+
+	require.NoError(t, c.Delete(1))
+
+	_, found, err = c.Read(1)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = l2.Get(1)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+// failingStore is a Store whose every operation fails.
+type failingStore struct{}
+
+var errUnavailable = errors.New("store unavailable")
+
+func (failingStore) Get(int) (string, bool, error) { return "", false, errUnavailable }
+func (failingStore) Set(int, string) error         { return errUnavailable }
+func (failingStore) Delete(int) error              { return errUnavailable }
+
+func TestTiered_errors(t *testing.T) {
+	t.Parallel()
+
+	l1 := cache.New[int, string](5, time.Minute)
+	l1.Upsert(1, "stale")
+	c := cache.NewTiered[int, string](l1, failingStore{})
+
+	err := c.Upsert(1, "eins")
+	assert.ErrorIs(t, err, errUnavailable)
+
+	// The first tier mustn't keep a value the second tier doesn't have.
+	_, found := l1.Read(1)
+	assert.False(t, found)
+
+	_, _, err = c.Read(1)
+	assert.ErrorIs(t, err, errUnavailable)
+
+	l1.Upsert(2, "zwei")
+	err = c.Delete(2)
+	assert.ErrorIs(t, err, errUnavailable)
+
+	_, found = l1.Read(2)
+	assert.False(t, found)
+}
+
+// gatedStore is a Store whose Get reads the value, signals it was entered, and waits for the gate to be opened.
+type gatedStore struct {
+	cache.Store[int, string]
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (s gatedStore) Get(key int) (string, bool, error) {
+	v, found, err := s.Store.Get(key)
+	s.entered <- struct{}{}
+	<-s.gate
+
+	return v, found, err
+}
+
+func TestTiered_Read_concurrentDelete(t *testing.T) {
+	t.Parallel()
+
+	fs, err := cache.NewFileStore[int, string](t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, fs.Set(1, "eins"))
+
+	l2 := gatedStore{Store: fs, entered: make(chan struct{}), gate: make(chan struct{})}
+	l1 := cache.New[int, string](5, time.Minute)
+	c := cache.NewTiered[int, string](l1, l2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = c.Read(1)
+	}()
+
+	// The key is deleted while the read is waiting on the second tier.
+	<-l2.entered
+	require.NoError(t, c.Delete(1))
+	close(l2.gate)
+	<-done
+
+	// The value read before the deletion mustn't resurrect the key in the first tier.
+	_, found := l1.Read(1)
+	assert.False(t, found)
+}
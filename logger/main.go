@@ -14,4 +14,6 @@ func main() {
 	lgr.Debugf("Make the zero (%d) value useful.", 0)
 
 	lgr.Infof("Hallo, %d %v", 2022, time.Now())
+
+	lgr.With("proverb", 14).Info("Clear is better than clever.", "author", "Rob Pike")
 }
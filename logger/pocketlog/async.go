@@ -0,0 +1,193 @@
+package pocketlog
+
+import "sync"
+
+// OverflowPolicy defines what an asynchronous logger does with a message when its buffer is full.
+type OverflowPolicy byte
+
+const (
+	// OverflowBlock waits for the buffer to have room for the message. This is the default policy.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the message that doesn't fit in the buffer.
+	// Messages of level Error and above are never dropped: they wait for room in the buffer.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest message of the buffer to make room for the new one.
+	// Messages of level Error and above are never dropped: a new message waits for room instead.
+	OverflowDropOldest
+)
+
+// String implements the fmt.Stringer interface.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop newest"
+	case OverflowDropOldest:
+		return "drop oldest"
+	default:
+		return ""
+	}
+}
+
+// droppedMessage is the message of the record reporting how many messages were dropped.
+const droppedMessage = "pocketlog: messages dropped, buffer full"
+
+// asyncWriter holds records in a bounded ring buffer, drained by a background goroutine.
+type asyncWriter struct {
+	mu sync.Mutex
+	// cond is signalled every time records are added to or removed from the buffer, and when the writer is closed.
+	cond   *sync.Cond
+	policy OverflowPolicy
+
+	// records is the ring buffer. The oldest record is at index head, and count records are buffered.
+	records []Record
+	head    int
+	count   int
+	// dropped is the number of records dropped since the last report.
+	dropped int
+	// busy is true while the background goroutine writes records it took from the buffer.
+	busy   bool
+	closed bool
+	done   chan struct{}
+
+	// write encodes a record and prints it to the outputs.
+	write func(r Record)
+	// report returns the record reporting that a number of records were dropped.
+	report func(dropped int) Record
+}
+
+// newAsyncWriter returns an asyncWriter buffering up to size records, and starts its background goroutine.
+func newAsyncWriter(size int, policy OverflowPolicy, write func(Record), report func(int) Record) *asyncWriter {
+	w := &asyncWriter{
+		policy:  policy,
+		records: make([]Record, max(size, 1)),
+		done:    make(chan struct{}),
+		write:   write,
+		report:  report,
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	go w.drain()
+
+	return w
+}
+
+// enqueue adds a record to the buffer, applying the overflow policy if it is full.
+// Once the writer is closed, records are written synchronously.
+func (w *asyncWriter) enqueue(r Record) {
+	w.mu.Lock()
+
+	for w.count == len(w.records) && !w.closed && !w.mayDrop(r) {
+		w.cond.Wait()
+	}
+
+	if w.closed {
+		w.mu.Unlock()
+		w.write(r)
+		return
+	}
+
+	if w.count == len(w.records) {
+		w.dropped++
+		if w.policy == OverflowDropNewest {
+			w.mu.Unlock()
+			return
+		}
+
+		// OverflowDropOldest: the new record takes the place of the oldest one.
+		w.head = (w.head + 1) % len(w.records)
+		w.count--
+	}
+
+	w.records[(w.head+w.count)%len(w.records)] = r
+	w.count++
+	w.cond.Broadcast()
+	w.mu.Unlock()
+}
+
+// mayDrop returns whether the overflow policy can drop a record to make room for r in a full buffer.
+// Records of level Error and above are never dropped. It must be called while holding the lock.
+func (w *asyncWriter) mayDrop(r Record) bool {
+	switch w.policy {
+	case OverflowDropNewest:
+		return r.Level < LevelError
+	case OverflowDropOldest:
+		return w.records[w.head].Level < LevelError
+	default:
+		return false
+	}
+}
+
+// drain writes the buffered records to the outputs, until the writer is closed and the buffer is empty.
+func (w *asyncWriter) drain() {
+	defer close(w.done)
+
+	for {
+		w.mu.Lock()
+		for w.count == 0 && w.dropped == 0 && !w.closed {
+			w.cond.Wait()
+		}
+
+		if w.count == 0 && w.dropped == 0 {
+			// The writer is closed, and everything was written.
+			w.mu.Unlock()
+			return
+		}
+
+		batch, dropped := w.take()
+		w.busy = true
+		// Make room for the blocked callers.
+		w.cond.Broadcast()
+		w.mu.Unlock()
+
+		if dropped != 0 {
+			w.write(w.report(dropped))
+		}
+
+		for _, r := range batch {
+			w.write(r)
+		}
+
+		w.mu.Lock()
+		w.busy = false
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// take empties the buffer, and returns its records in order, with the number of dropped records.
+// It must be called while holding the lock.
+func (w *asyncWriter) take() ([]Record, int) {
+	batch := make([]Record, w.count)
+	for i := range batch {
+		index := (w.head + i) % len(w.records)
+		batch[i] = w.records[index]
+		w.records[index] = Record{}
+	}
+
+	dropped := w.dropped
+	w.head, w.count, w.dropped = 0, 0, 0
+
+	return batch, dropped
+}
+
+// flush waits until all the records buffered so far are written.
+func (w *asyncWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for w.count != 0 || w.dropped != 0 || w.busy {
+		w.cond.Wait()
+	}
+}
+
+// close writes the buffered records, and stops the background goroutine. It is safe to call several times.
+func (w *asyncWriter) close() {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+}
@@ -0,0 +1,242 @@
+package pocketlog_test
+
+import (
+	"fmt"
+	"goprojects/logger/pocketlog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_WithAsync_flush(t *testing.T) {
+	const messages = 1000
+
+	tw := &testWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithAsync(16, pocketlog.OverflowBlock))
+	defer lgr.Close()
+
+	expected := strings.Builder{}
+	for i := range messages {
+		lgr.Info(infoMessage, "i", i)
+		expected.WriteString(fmt.Sprintf(`{"level":"[INFO]","message":"%s","i":%d}`+"\n", infoMessage, i))
+	}
+
+	lgr.Flush()
+
+	if tw.contents != expected.String() {
+		t.Errorf("invalid contents, expected %d messages in order, got %q", messages, tw.contents)
+	}
+}
+
+func TestLogger_WithAsync_overflow(t *testing.T) {
+	tt := map[string]struct {
+		policy   pocketlog.OverflowPolicy
+		expected []string
+	}{
+		"drop newest": {
+			policy: pocketlog.OverflowDropNewest,
+			expected: []string{
+				`{"level":"[INFO]","message":"0"}`,
+				`{"level":"[WARN]","message":"pocketlog: messages dropped, buffer full","dropped":2}`,
+				`{"level":"[INFO]","message":"1"}`,
+				`{"level":"[INFO]","message":"2"}`,
+			},
+		},
+		"drop oldest": {
+			policy: pocketlog.OverflowDropOldest,
+			expected: []string{
+				`{"level":"[INFO]","message":"0"}`,
+				`{"level":"[WARN]","message":"pocketlog: messages dropped, buffer full","dropped":2}`,
+				`{"level":"[INFO]","message":"3"}`,
+				`{"level":"[INFO]","message":"4"}`,
+			},
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			gw := newGatedWriter()
+			lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(gw), pocketlog.WithAsync(2, tc.policy))
+			defer lgr.Close()
+
+			// The first message is taken by the background goroutine, which then waits for the gate to open.
+			lgr.Info("0")
+			<-gw.entered
+
+			for i := 1; i <= 4; i++ {
+				lgr.Info(fmt.Sprint(i))
+			}
+
+			close(gw.gate)
+			lgr.Flush()
+
+			got := strings.Split(strings.TrimSuffix(gw.contents(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("invalid contents, expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogger_WithAsync_errorsNotDropped(t *testing.T) {
+	tt := map[string]struct {
+		policy pocketlog.OverflowPolicy
+		// blocking logs, from another goroutine, a message that must wait for room in the buffer.
+		blocking func(lgr *pocketlog.Logger)
+		expected []string
+	}{
+		"drop newest": {
+			policy: pocketlog.OverflowDropNewest,
+			blocking: func(lgr *pocketlog.Logger) {
+				lgr.Error("3")
+			},
+			expected: []string{
+				`{"level":"[INFO]","message":"0"}`,
+				`{"level":"[WARN]","message":"pocketlog: messages dropped, buffer full","dropped":1}`,
+				`{"level":"[ERROR]","message":"1"}`,
+				`{"level":"[ERROR]","message":"3"}`,
+			},
+		},
+		"drop oldest": {
+			policy: pocketlog.OverflowDropOldest,
+			blocking: func(lgr *pocketlog.Logger) {
+				lgr.Info("2")
+			},
+			expected: []string{
+				`{"level":"[INFO]","message":"0"}`,
+				`{"level":"[ERROR]","message":"1"}`,
+				`{"level":"[INFO]","message":"2"}`,
+			},
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			gw := newGatedWriter()
+			lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(gw), pocketlog.WithAsync(1, tc.policy))
+			defer lgr.Close()
+
+			// The first message is taken by the background goroutine, which then waits for the gate to open.
+			lgr.Info("0")
+			<-gw.entered
+
+			// The error fills the buffer: it can't be dropped to make room for the next messages.
+			// With OverflowDropNewest, an info message is still dropped.
+			lgr.Error("1")
+			if tc.policy == pocketlog.OverflowDropNewest {
+				lgr.Info("2")
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tc.blocking(lgr)
+			}()
+
+			select {
+			case <-done:
+				t.Error("expected the message to wait for room in the buffer")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			close(gw.gate)
+			<-done
+			lgr.Flush()
+
+			got := strings.Split(strings.TrimSuffix(gw.contents(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("invalid contents, expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogger_Close(t *testing.T) {
+	gw := newGatedWriter()
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(gw), pocketlog.WithAsync(4, pocketlog.OverflowBlock))
+
+	lgr.Info("before")
+	<-gw.entered
+	lgr.With("child", true).Info("buffered")
+
+	close(gw.gate)
+	lgr.Close()
+
+	expected := `{"level":"[INFO]","message":"before"}` + "\n" +
+		`{"level":"[INFO]","message":"buffered","child":true}` + "\n"
+	if gw.contents() != expected {
+		t.Fatalf("invalid contents after Close, expected %q, got %q", expected, gw.contents())
+	}
+
+	// Messages logged after Close are written synchronously.
+	lgr.Info("after")
+	lgr.Close()
+
+	expected += `{"level":"[INFO]","message":"after"}` + "\n"
+	if gw.contents() != expected {
+		t.Errorf("invalid contents after a second Close, expected %q, got %q", expected, gw.contents())
+	}
+}
+
+func TestLogger_WithAsync_concurrent(t *testing.T) {
+	const (
+		goroutines = 10
+		messages   = 100
+	)
+
+	lw := &lineWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(lw), pocketlog.WithAsync(8, pocketlog.OverflowBlock))
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range messages {
+				lgr.Info(infoMessage)
+			}
+		}()
+	}
+	wg.Wait()
+	lgr.Close()
+
+	if len(lw.lines) != goroutines*messages {
+		t.Errorf("expected %d lines with a blocking buffer, got %d", goroutines*messages, len(lw.lines))
+	}
+}
+
+// gatedWriter blocks its first call to Write until the gate is closed.
+type gatedWriter struct {
+	entered chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{entered: make(chan struct{}), gate: make(chan struct{})}
+}
+
+// Write implements the io.Writer interface
+func (gw *gatedWriter) Write(p []byte) (n int, err error) {
+	gw.once.Do(func() {
+		close(gw.entered)
+		<-gw.gate
+	})
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	return gw.buf.Write(p)
+}
+
+// contents returns everything written so far.
+func (gw *gatedWriter) contents() string {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	return gw.buf.String()
+}
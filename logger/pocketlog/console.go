@@ -0,0 +1,87 @@
+package pocketlog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ANSI escape codes used to color the levels.
+const (
+	colorReset  = "\x1b[0m"
+	colorGrey   = "\x1b[90m"
+	colorBlue   = "\x1b[34m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorPurple = "\x1b[35m"
+)
+
+// ConsoleEncoder writes records for humans reading a terminal, such as
+//
+//	12:30:00.123 [INFO] game created gameID=42
+//
+// The time is shortened to the time of day, and the level is colored, unless NoColor is set.
+// Messages holding characters that aren't printable, such as newlines, are quoted, to keep one record per line.
+// It is meant for local development: use JSONEncoder or LogfmtEncoder for logs read by machines.
+type ConsoleEncoder struct {
+	NoColor bool
+}
+
+// Encode implements the Encoder interface.
+func (e ConsoleEncoder) Encode(r Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if !r.Time.IsZero() {
+		buf.WriteString(r.Time.Format(time.TimeOnly + ".000"))
+		buf.WriteByte(' ')
+	}
+
+	color := levelColor(r.Level)
+	if !e.NoColor && color != "" {
+		buf.WriteString(color + r.Level.String() + colorReset)
+	} else {
+		buf.WriteString(r.Level.String())
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(consoleMessage(r.Message))
+
+	if r.Caller != "" {
+		writeLogfmtPair(&buf, callerKey, r.Caller)
+	}
+
+	for _, f := range r.Fields {
+		writeLogfmtPair(&buf, fieldKey(f.Key), f.Value)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// consoleMessage returns the message as is, or quoted if it holds characters that aren't printable.
+func consoleMessage(msg string) string {
+	if strings.ContainsFunc(msg, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return strconv.Quote(msg)
+	}
+
+	return msg
+}
+
+// levelColor returns the ANSI color of a level.
+func levelColor(lvl Level) string {
+	switch lvl {
+	case LevelTrace, LevelDebug:
+		return colorGrey
+	case LevelInfo:
+		return colorBlue
+	case LevelWarn:
+		return colorYellow
+	case LevelError:
+		return colorRed
+	case LevelFatal:
+		return colorPurple
+	default:
+		return ""
+	}
+}
@@ -4,11 +4,56 @@ Package pocketlog exposes an API to log your work.
 First, instantiate a logger with pocketlog.New, and giving it a threshold level.
 Messages of lesser criticality won't be logged.
 
-Sharing the logger is the responsibility of the caller.
+The logger is safe for concurrent use. Each message is written to the output as one complete line,
+in a single call to Write, so that messages logged by different goroutines are never interleaved.
+A logger and its children, returned by With, take turns to write to the output.
 
-The logger can be called to log messages on three levels:
+To keep slow outputs off the path of the callers, WithAsync buffers the messages and writes them in the background.
+Flush waits for the buffered messages to be written, and Close must be called before the program exits:
+
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithAsync(1024, pocketlog.OverflowDropOldest))
+	defer lgr.Close()
+
+The logger can be called to log messages on six levels:
+  - Trace: detailed messages to follow the execution of the code
   - Debug: mostly used to debug code, follow step-by-step processes
   - Info: valuable messages providing  insights to the milestones of a process
+  - Warn: unexpected situations that don't prevent the process from working
   - Error: error messages to understand what went wrong
+  - Fatal: errors that stop the program, which exits after logging them
+
+The threshold can be changed at any time with SetLevel, for instance after parsing it with ParseLevel.
+
+Each level has two methods. Messages are either formatted, with the methods ending in f, such as Infof,
+or structured, with the methods named after the level, such as Info, which take alternating keys and values:
+
+	lgr.Info("game created", "gameID", id)
+
+Log and Logf do the same for a level given as a parameter.
+
+With returns a child logger that attaches key-value pairs to all its messages.
+
+Messages can also carry their time, with WithTimestamp, and the location of the code that logged them, with WithCaller.
+
+Messages are encoded in JSON by default. WithEncoder selects another format, such as LogfmtEncoder,
+or ConsoleEncoder for humans during local development.
+
+WithSink sends the messages to several outputs, each with its own minimum level and encoder:
+
+	lgr := pocketlog.New(pocketlog.LevelDebug,
+		pocketlog.WithSink(os.Stderr, pocketlog.LevelError, pocketlog.ConsoleEncoder{}),
+		pocketlog.WithSink(file, pocketlog.LevelDebug, pocketlog.JSONEncoder{}),
+	)
+
+WithSampling keeps hot loops from flooding the outputs: it only logs the first occurrences of an identical message
+during an interval, then one every few occurrences, and reports how many were suppressed at the end of the interval.
+Errors and fatal messages are never sampled.
+
+To log to disk, give WithOutput a RotatingFile, which rolls the file by size or time, keeps a number of backups,
+and can compress them. Call its Reopen method on SIGHUP when an external tool rotates the file.
+
+Code using log/slog can log through a Logger, with NewSlogLogger or NewHandler:
+
+	slog.SetDefault(pocketlog.NewSlogLogger(lgr))
 */
 package pocketlog
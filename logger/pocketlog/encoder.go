@@ -0,0 +1,96 @@
+package pocketlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Record holds everything known about a logged message.
+type Record struct {
+	// Time is the zero time when timestamps aren't required.
+	Time    time.Time
+	Level   Level
+	Message string
+	// Caller is empty when the caller isn't required.
+	Caller string
+	Fields []Field
+}
+
+// Encoder turns a record into a line of output, without the trailing newline.
+// All the encoders write the same fields: time, level, message, caller, and the key-value pairs, in this order.
+// The keys of the key-value pairs that collide with the other fields are prefixed, such as "fields.level".
+type Encoder interface {
+	Encode(r Record) ([]byte, error)
+}
+
+// Keys of the fields that every record carries.
+const (
+	timeKey    = "time"
+	levelKey   = "level"
+	messageKey = "message"
+	callerKey  = "caller"
+)
+
+// fieldKey returns the key of a field, prefixed with "fields." if it is one of the keys that every record carries,
+// so that a field can't pass for the time, level, message or caller of the record.
+func fieldKey(key string) string {
+	switch key {
+	case timeKey, levelKey, messageKey, callerKey:
+		return "fields." + key
+	default:
+		return key
+	}
+}
+
+// JSONEncoder writes records as JSON objects, such as {"level":"[INFO]","message":"game created","gameID":42}.
+// This is the default encoder.
+type JSONEncoder struct{}
+
+// Encode implements the Encoder interface.
+func (JSONEncoder) Encode(r Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	if !r.Time.IsZero() {
+		writeJSONPair(&buf, timeKey, r.Time.Format(time.RFC3339Nano))
+		buf.WriteByte(',')
+	}
+
+	writeJSONPair(&buf, levelKey, r.Level.String())
+	buf.WriteByte(',')
+	writeJSONPair(&buf, messageKey, r.Message)
+
+	if r.Caller != "" {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, callerKey, r.Caller)
+	}
+
+	for _, f := range r.Fields {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, fieldKey(f.Key), f.Value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// writeJSONPair writes a "key":value pair. Errors are written as their message,
+// and values that can't be encoded in JSON are written as their default format.
+func writeJSONPair(buf *bytes.Buffer, key string, value any) {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprintf("%+v", value))
+	}
+	buf.Write(v)
+}
@@ -0,0 +1,135 @@
+package pocketlog_test
+
+import (
+	"errors"
+	"goprojects/logger/pocketlog"
+	"testing"
+	"time"
+)
+
+func TestEncoders(t *testing.T) {
+	record := pocketlog.Record{
+		Time:    time.Date(2024, 4, 25, 12, 30, 0, 123456789, time.UTC),
+		Level:   pocketlog.LevelError,
+		Message: "guess rejected",
+		Caller:  "guess/handler.go:42",
+		Fields: []pocketlog.Field{
+			{Key: "gameID", Value: "g1"},
+			{Key: "attempt", Value: 3},
+			{Key: "err", Value: errors.New(`word "hello" not in corpus`)},
+		},
+	}
+
+	tt := map[string]struct {
+		encoder  pocketlog.Encoder
+		expected string
+	}{
+		"json": {
+			encoder: pocketlog.JSONEncoder{},
+			expected: `{"time":"2024-04-25T12:30:00.123456789Z","level":"[ERROR]","message":"guess rejected",` +
+				`"caller":"guess/handler.go:42","gameID":"g1","attempt":3,"err":"word \"hello\" not in corpus"}`,
+		},
+		"logfmt": {
+			encoder: pocketlog.LogfmtEncoder{},
+			expected: `time=2024-04-25T12:30:00.123456789Z level=[ERROR] message="guess rejected" ` +
+				`caller=guess/handler.go:42 gameID=g1 attempt=3 err="word \"hello\" not in corpus"`,
+		},
+		"console": {
+			encoder: pocketlog.ConsoleEncoder{},
+			expected: "12:30:00.123 \x1b[31m[ERROR]\x1b[0m guess rejected " +
+				`caller=guess/handler.go:42 gameID=g1 attempt=3 err="word \"hello\" not in corpus"`,
+		},
+		"console without color": {
+			encoder: pocketlog.ConsoleEncoder{NoColor: true},
+			expected: "12:30:00.123 [ERROR] guess rejected " +
+				`caller=guess/handler.go:42 gameID=g1 attempt=3 err="word \"hello\" not in corpus"`,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.encoder.Encode(record)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(got) != tc.expected {
+				t.Errorf("invalid encoding, expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoder_minimal(t *testing.T) {
+	got, err := pocketlog.LogfmtEncoder{}.Encode(pocketlog.Record{
+		Level:   pocketlog.LevelInfo,
+		Message: "",
+		Fields:  []pocketlog.Field{{Key: "bad key=", Value: nil}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `level=[INFO] message="" bad_key_=null`
+	if string(got) != expected {
+		t.Errorf("invalid encoding, expected %q, got %q", expected, got)
+	}
+}
+
+func TestLogger_WithEncoder(t *testing.T) {
+	tw := &testWriter{}
+
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithEncoder(pocketlog.LogfmtEncoder{}))
+	lgr.With("gameID", "g1").Infof("guess %d", 2)
+
+	expected := `level=[INFO] message="guess 2" gameID=g1` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestEncoders_reservedKeys(t *testing.T) {
+	tt := map[string]struct {
+		encoder  pocketlog.Encoder
+		expected string
+	}{
+		"json": {
+			encoder: pocketlog.JSONEncoder{},
+			expected: `{"level":"[INFO]","message":"hi","fields.level":"spoof","fields.message":"spoof",` +
+				`"fields.time":"spoof","fields.caller":"spoof"}` + "\n",
+		},
+		"logfmt": {
+			encoder: pocketlog.LogfmtEncoder{},
+			expected: `level=[INFO] message=hi fields.level=spoof fields.message=spoof ` +
+				`fields.time=spoof fields.caller=spoof` + "\n",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			tw := &testWriter{}
+
+			lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithEncoder(tc.encoder))
+			lgr.Info("hi", "level", "spoof", "message", "spoof", "time", "spoof", "caller", "spoof")
+
+			if tw.contents != tc.expected {
+				t.Errorf("fields must not pass for the keys of the record, expected %q, got %q", tc.expected, tw.contents)
+			}
+		})
+	}
+}
+
+func TestConsoleEncoder_multiline(t *testing.T) {
+	got, err := pocketlog.ConsoleEncoder{NoColor: true}.Encode(pocketlog.Record{
+		Level:   pocketlog.LevelInfo,
+		Message: "line1\nline2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `[INFO] "line1\nline2"`
+	if string(got) != expected {
+		t.Errorf("invalid encoding, expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,15 @@
+package pocketlog
+
+// Error is used to define sentinel errors.
+type Error string
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrUnknownLevel is returned when a level name can't be parsed.
+const ErrUnknownLevel = Error("unknown level")
+
+// ErrFileClosed is returned when using a RotatingFile after closing it.
+const ErrFileClosed = Error("file already closed")
@@ -0,0 +1,30 @@
+package pocketlog
+
+// badKey is the key used for values that aren't preceded by a string key.
+const badKey = "!BADKEY"
+
+// Field is a key-value pair attached to a log message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// parseFields turns a list of alternating keys and values into fields.
+// A value without a string key, such as a trailing value, is given the key "!BADKEY".
+func parseFields(kv []any) []Field {
+	fields := make([]Field, 0, (len(kv)+1)/2)
+
+	for len(kv) > 0 {
+		key, ok := kv[0].(string)
+		if !ok || len(kv) == 1 {
+			fields = append(fields, Field{Key: badKey, Value: kv[0]})
+			kv = kv[1:]
+			continue
+		}
+
+		fields = append(fields, Field{Key: key, Value: kv[1]})
+		kv = kv[2:]
+	}
+
+	return fields
+}
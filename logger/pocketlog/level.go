@@ -1,27 +1,65 @@
 package pocketlog
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Level represents an available logging level
 type Level byte
 
 const (
-	// LevelDebug represents the lowest level of log, mostly used for debugging purposes.
-	LevelDebug Level = iota
+	// LevelTrace represents the lowest level of log, used to follow the execution of the code in detail.
+	LevelTrace Level = iota
+	// LevelDebug represents a logging level mostly used for debugging purposes.
+	LevelDebug
 	// LevelInfo represents a logging level that contains information deemed valuable.
 	LevelInfo
-	// LevelError represents the highest logging level, only to be used to trace errors.
+	// LevelWarn represents a logging level for unexpected situations that don't prevent the process from working.
+	LevelWarn
+	// LevelError represents a logging level used to trace errors.
 	LevelError
+	// LevelFatal represents the highest logging level, for errors that stop the program.
+	LevelFatal
 )
 
 // String implements the fmt.Stringer interface
 func (lvl Level) String() string {
 	switch lvl {
+	case LevelTrace:
+		return "[TRACE]"
 	case LevelDebug:
 		return "[DEBUG]"
 	case LevelInfo:
 		return "[INFO]"
+	case LevelWarn:
+		return "[WARN]"
 	case LevelError:
 		return "[ERROR]"
+	case LevelFatal:
+		return "[FATAL]"
 	default:
 		return ""
 	}
 }
+
+// ParseLevel returns the level matching a name, such as "info", case-insensitively.
+// The names written in the logs, such as "[INFO]", are accepted too, as well as "warning".
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(name), "[]")) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownLevel, name)
+	}
+}
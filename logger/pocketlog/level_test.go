@@ -0,0 +1,130 @@
+package pocketlog_test
+
+import (
+	"errors"
+	"goprojects/logger/pocketlog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tt := map[string]struct {
+		name     string
+		expected pocketlog.Level
+		err      error
+	}{
+		"trace":           {name: "trace", expected: pocketlog.LevelTrace},
+		"debug uppercase": {name: "DEBUG", expected: pocketlog.LevelDebug},
+		"info":            {name: "Info", expected: pocketlog.LevelInfo},
+		"warn":            {name: "warn", expected: pocketlog.LevelWarn},
+		"warning":         {name: "warning", expected: pocketlog.LevelWarn},
+		"error as logged": {name: "[ERROR]", expected: pocketlog.LevelError},
+		"fatal":           {name: " fatal ", expected: pocketlog.LevelFatal},
+		"unknown":         {name: "verbose", err: pocketlog.ErrUnknownLevel},
+		"empty":           {name: "", err: pocketlog.ErrUnknownLevel},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := pocketlog.ParseLevel(tc.name)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+
+			if err == nil && got != tc.expected {
+				t.Errorf("expected level %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogger_TraceWarn(t *testing.T) {
+	tw := &testWriter{}
+
+	lgr := pocketlog.New(pocketlog.LevelTrace, pocketlog.WithOutput(tw))
+	lgr.Trace(debugMessage)
+	lgr.Warnf("%s", infoMessage)
+
+	expected := `{"level":"[TRACE]","message":"` + debugMessage + "\"}\n" +
+		`{"level":"[WARN]","message":"` + infoMessage + "\"}\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	tw := &testWriter{}
+
+	parent := pocketlog.New(pocketlog.LevelError, pocketlog.WithOutput(tw))
+	child := parent.With("child", true)
+
+	child.Info(infoMessage)
+	if tw.contents != "" {
+		t.Fatalf("nothing should be logged below the threshold, got %q", tw.contents)
+	}
+
+	parent.SetLevel(pocketlog.LevelInfo)
+
+	if child.Level() != pocketlog.LevelInfo {
+		t.Errorf("child logger should follow the level of its parent, got %s", child.Level())
+	}
+
+	child.Info(infoMessage)
+	expected := `{"level":"[INFO]","message":"` + infoMessage + `","child":true}` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestLogger_SetLevel_concurrent(t *testing.T) {
+	// Below the threshold, nothing is written, so only the level is shared between goroutines.
+	lgr := pocketlog.New(pocketlog.LevelFatal, pocketlog.WithOutput(&testWriter{}))
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			lgr.SetLevel(pocketlog.Level(i%2) + pocketlog.LevelError)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			lgr.Debug(debugMessage)
+		}
+	}()
+
+	wg.Wait()
+}
+
+// fatalEnv is set when the test binary runs as a subprocess that must call Fatal.
+const fatalEnv = "POCKETLOG_TEST_FATAL"
+
+func TestLogger_Fatal(t *testing.T) {
+	if os.Getenv(fatalEnv) == "1" {
+		lgr := pocketlog.New(pocketlog.LevelError, pocketlog.WithOutput(os.Stdout))
+		lgr.Fatal("game over", "score", 0)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLogger_Fatal$")
+	cmd.Env = append(os.Environ(), fatalEnv+"=1")
+
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1, got %v", err)
+	}
+
+	expected := `{"level":"[FATAL]","message":"game over","score":0}`
+	if !strings.Contains(string(out), expected) {
+		t.Errorf("expected output to contain %q, got %q", expected, out)
+	}
+}
@@ -0,0 +1,99 @@
+package pocketlog
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LogfmtEncoder writes records as space-separated key=value pairs,
+// such as level=[INFO] message="game created" gameID=42.
+type LogfmtEncoder struct{}
+
+// Encode implements the Encoder interface.
+func (LogfmtEncoder) Encode(r Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if !r.Time.IsZero() {
+		writeLogfmtPair(&buf, timeKey, r.Time.Format(time.RFC3339Nano))
+	}
+
+	writeLogfmtPair(&buf, levelKey, r.Level.String())
+	writeLogfmtPair(&buf, messageKey, r.Message)
+
+	if r.Caller != "" {
+		writeLogfmtPair(&buf, callerKey, r.Caller)
+	}
+
+	for _, f := range r.Fields {
+		writeLogfmtPair(&buf, fieldKey(f.Key), f.Value)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeLogfmtPair writes a key=value pair, preceded by a space if it isn't the first one.
+func writeLogfmtPair(buf *bytes.Buffer, key string, value any) {
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(logfmtKey(key))
+	buf.WriteByte('=')
+	buf.WriteString(logfmtValue(value))
+}
+
+// logfmtKey removes the characters that aren't allowed in a key: spaces, '=' and '"'.
+func logfmtKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+
+	if key == "" {
+		return badKey
+	}
+
+	return key
+}
+
+// logfmtValue formats a value, quoting it if needed. Errors are written as their message.
+func logfmtValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case nil:
+		return "null"
+	default:
+		s = fmt.Sprintf("%+v", v)
+	}
+
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// needsQuoting returns whether a value is empty, or holds characters that would break the key=value pairs.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,87 +1,334 @@
 package pocketlog
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
 )
 
-// Logger is used to log information
+// Logger is used to log information.
+// It is safe for concurrent use: each message is written to the outputs as one complete line, in a single call.
 type Logger struct {
-	threshold        Level
-	output           io.Writer
+	// threshold is shared with the child loggers, so that SetLevel affects them too.
+	threshold *atomic.Uint32
+	// output and encoder configure the sink used when none is set with WithSink.
+	output  io.Writer
+	encoder Encoder
+	// sinks are shared with the child loggers, so that they don't write to an output at the same time.
+	sinks            []sink
 	maxMessageLength uint
+	fields           []Field
+	withTimestamp    bool
+	withCaller       bool
+	now              func() time.Time
+
+	// asyncSize and overflow configure the asynchronous mode, enabled when asyncSize is positive.
+	asyncSize int
+	overflow  OverflowPolicy
+	// async is shared with the child loggers, so that they use the same buffer.
+	async *asyncWriter
+
+	// sampleFirst, sampleThereafter and sampleInterval configure the sampling, enabled when sampleInterval is positive.
+	sampleFirst      int
+	sampleThereafter int
+	sampleInterval   time.Duration
+	// sampler is shared with the child loggers, so that they count the same occurrences.
+	sampler *sampler
 }
 
 // New returns a logger, ready to log at the required threshold.
 // Give it a list of configuration functions to tune it at your will.
 // The default output is Stdout.
 // There is no default maximum length - messages aren't trimmed.
+// By default, messages have neither timestamp nor caller, and are encoded in JSON.
 func New(threshold Level, opts ...Option) *Logger {
-	lgr := &Logger{threshold: threshold, output: os.Stdout, maxMessageLength: 0}
+	lgr := &Logger{
+		threshold:        new(atomic.Uint32),
+		output:           os.Stdout,
+		maxMessageLength: 0,
+		now:              time.Now,
+		encoder:          JSONEncoder{},
+	}
+	lgr.threshold.Store(uint32(threshold))
 
 	for _, configFunc := range opts {
 		configFunc(lgr)
 	}
 
+	if len(lgr.sinks) == 0 {
+		lgr.sinks = []sink{newSink(lgr.output, LevelTrace, lgr.encoder)}
+	}
+
+	if lgr.asyncSize > 0 {
+		lgr.async = newAsyncWriter(lgr.asyncSize, lgr.overflow, lgr.dispatch, lgr.droppedRecord)
+	}
+
+	if lgr.sampleInterval > 0 {
+		lgr.sampler = newSampler(lgr.sampleFirst, lgr.sampleThereafter, lgr.sampleInterval, lgr.now())
+		go lgr.sampler.run(lgr.now, lgr.writeSummary)
+	}
+
 	return lgr
 }
 
+// Level returns the current threshold of the logger.
+func (l *Logger) Level() Level {
+	return Level(l.threshold.Load())
+}
+
+// SetLevel changes the threshold of the logger, and of all the loggers sharing its configuration:
+// its parent and children obtained through With. It is safe to call while logging.
+func (l *Logger) SetLevel(threshold Level) {
+	l.threshold.Store(uint32(threshold))
+}
+
+// enabled returns whether a message at the given level should be logged.
+func (l *Logger) enabled(lvl Level) bool {
+	return l.Level() <= lvl
+}
+
+// With returns a child logger that attaches the given key-value pairs to every message it logs,
+// in addition to the ones of its parent. Keys must be strings. The parent logger isn't affected.
+func (l *Logger) With(kv ...any) *Logger {
+	child := *l
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], parseFields(kv)...)
+
+	return &child
+}
+
+// Trace prints a message with key-value pairs if the log level is trace.
+func (l *Logger) Trace(msg string, kv ...any) {
+	l.logkv(LevelTrace, msg, kv)
+}
+
+// Debug prints a message with key-value pairs if the log level is debug or higher.
+func (l *Logger) Debug(msg string, kv ...any) {
+	l.logkv(LevelDebug, msg, kv)
+}
+
+// Info prints a message with key-value pairs if the log level is info or higher.
+func (l *Logger) Info(msg string, kv ...any) {
+	l.logkv(LevelInfo, msg, kv)
+}
+
+// Warn prints a message with key-value pairs if the log level is warn or higher.
+func (l *Logger) Warn(msg string, kv ...any) {
+	l.logkv(LevelWarn, msg, kv)
+}
+
+// Error prints a message with key-value pairs if the log level is error or higher.
+func (l *Logger) Error(msg string, kv ...any) {
+	l.logkv(LevelError, msg, kv)
+}
+
+// Fatal prints a message with key-value pairs, then stops the program with os.Exit(1).
+// Buffered messages are flushed before exiting.
+func (l *Logger) Fatal(msg string, kv ...any) {
+	l.logkv(LevelFatal, msg, kv)
+	l.Flush()
+	os.Exit(1)
+}
+
+// Log prints a message with key-value pairs if the log level is high enough.
+// The pairs are given as alternating keys and values, such as "gameID", id.
+func (l *Logger) Log(lvl Level, msg string, kv ...any) {
+	l.logkv(lvl, msg, kv)
+}
+
+// Tracef formats and prints a message if the log level is trace.
+func (l *Logger) Tracef(format string, args ...any) {
+	l.logf(LevelTrace, format, args)
+}
+
 // Debugf formats and prints a message if the log level is debug or higher.
 func (l *Logger) Debugf(format string, args ...any) {
-	l.Logf(LevelDebug, format, args...)
+	l.logf(LevelDebug, format, args)
 }
 
 // Infof formats and prints a message if the log level is info or higher.
 func (l *Logger) Infof(format string, args ...any) {
-	l.Logf(LevelInfo, format, args...)
+	l.logf(LevelInfo, format, args)
+}
 
+// Warnf formats and prints a message if the log level is warn or higher.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.logf(LevelWarn, format, args)
 }
 
 // Errorf formats and prints a message if the log level is error or higher.
 func (l *Logger) Errorf(format string, args ...any) {
-	l.Logf(LevelError, format, args...)
+	l.logf(LevelError, format, args)
+}
+
+// Fatalf formats and prints a message, then stops the program with os.Exit(1).
+// Buffered messages are flushed before exiting.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.logf(LevelFatal, format, args)
+	l.Flush()
+	os.Exit(1)
 }
 
 // Logf formats and prints a message if the log level is high enough
 func (l *Logger) Logf(lvl Level, format string, args ...any) {
-	if l.threshold > lvl {
+	l.logf(lvl, format, args)
+}
+
+// callerSkip is the number of stack frames between runtime.Callers and the caller of a public logging method,
+// through logkv or logf, and callerPC.
+const callerSkip = 4
+
+// logkv prints a message with key-value pairs if the log level is high enough.
+// It must be called directly by the public logging methods, for the caller to be found.
+func (l *Logger) logkv(lvl Level, msg string, kv []any) {
+	if !l.enabled(lvl) || !l.sample(lvl, msg) {
 		return
 	}
 
-	l.logf(lvl, format, args...)
+	l.log(lvl, msg, kv, l.callerPC(callerSkip))
 }
 
-// logf prints the message to the output.
-// Add decorations here, if any.
-func (l *Logger) logf(lvl Level, format string, args ...any) {
-	if l.output == nil {
-		l.output = os.Stdout
+// logf formats and prints a message if the log level is high enough.
+// It must be called directly by the public logging methods, for the caller to be found.
+func (l *Logger) logf(lvl Level, format string, args []any) {
+	if !l.enabled(lvl) {
+		return
 	}
 
-	contents := fmt.Sprintf(format, args...)
+	msg := fmt.Sprintf(format, args...)
+	if !l.sample(lvl, msg) {
+		return
+	}
+
+	l.log(lvl, msg, nil, l.callerPC(callerSkip))
+}
 
-	if l.maxMessageLength != 0 && uint(len([]rune(contents))) > l.maxMessageLength {
-		contents = string([]rune(contents))[:l.maxMessageLength] + "[TRIMMED]"
+// callerPC returns the program counter of the caller, skipping the given number of frames,
+// or 0 if the caller isn't required.
+func (l *Logger) callerPC(skip int) uintptr {
+	if !l.withCaller {
+		return 0
 	}
 
-	msg := message{
-		Level:   lvl.String(),
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+
+	return pcs[0]
+}
+
+// log prints the message, with the fields of the logger and the given key-value pairs, to the output.
+func (l *Logger) log(lvl Level, contents string, kv []any, pc uintptr) {
+	r := Record{
+		Level:   lvl,
 		Message: contents,
+		Fields:  append(l.fields[:len(l.fields):len(l.fields)], parseFields(kv)...),
 	}
 
-	formattedMessage, err := json.Marshal(msg)
-	if err != nil {
-		_, _ = fmt.Fprintf(l.output, "unable to format message for %v\n", contents)
+	if l.withTimestamp {
+		r.Time = l.now()
+	}
+
+	if pc != 0 {
+		r.Caller = caller(pc)
+	}
+
+	l.write(r)
+}
+
+// write prints the record to the sinks, or to the buffer in asynchronous mode.
+// Add decorations here, if any.
+func (l *Logger) write(r Record) {
+	if l.maxMessageLength != 0 && uint(len([]rune(r.Message))) > l.maxMessageLength {
+		r.Message = string([]rune(r.Message))[:l.maxMessageLength] + "[TRIMMED]"
+	}
+
+	if l.async != nil {
+		l.async.enqueue(r)
 		return
 	}
 
-	_, _ = fmt.Fprintln(l.output, string(formattedMessage))
+	l.dispatch(r)
+}
+
+// dispatch prints the record to every sink that accepts its level.
+func (l *Logger) dispatch(r Record) {
+	for _, s := range l.sinks {
+		s.write(r)
+	}
+}
+
+// droppedRecord returns the warning reporting that messages were dropped by the asynchronous mode.
+func (l *Logger) droppedRecord(dropped int) Record {
+	r := Record{Level: LevelWarn, Message: droppedMessage, Fields: []Field{{Key: "dropped", Value: dropped}}}
+	if l.withTimestamp {
+		r.Time = l.now()
+	}
+
+	return r
+}
+
+// Flush waits until all the messages logged so far are written to the output.
+// It only has an effect in asynchronous mode, enabled by WithAsync.
+func (l *Logger) Flush() {
+	if l.async != nil {
+		l.async.flush()
+	}
 }
 
-// message represents the JSON structure of the logged messages.
-type message struct {
-	Level   string `json:"level"`
-	Message string `json:"message"`
+// sample returns whether a message passes the sampling set with WithSampling,
+// and writes the summary of the previous interval if it is over.
+// Errors and fatal messages are never sampled: they are always logged.
+func (l *Logger) sample(lvl Level, msg string) bool {
+	if l.sampler == nil || lvl >= LevelError {
+		return true
+	}
+
+	allowed, summary := l.sampler.allow(lvl, msg, l.now())
+	l.writeSummary(summary)
+
+	return allowed
+}
+
+// writeSummary writes a record for each message suppressed by sampling, with the number of suppressed occurrences.
+func (l *Logger) writeSummary(summary []suppressed) {
+	for _, s := range summary {
+		r := Record{
+			Level:   s.level,
+			Message: suppressedMessage,
+			Fields:  []Field{{Key: "sampled", Value: s.message}, {Key: "suppressed", Value: s.count}},
+		}
+
+		if l.withTimestamp {
+			r.Time = l.now()
+		}
+
+		l.write(r)
+	}
+}
+
+// Close writes the buffered messages to the output, and stops the background goroutines
+// of the asynchronous mode and of the sampling, writing the summary of the suppressed messages.
+// Messages logged after Close are written synchronously, and aren't sampled anymore. Close doesn't close the output.
+// A logger and its children share the same buffer: closing one of them closes all of them.
+func (l *Logger) Close() {
+	if l.sampler != nil {
+		l.writeSummary(l.sampler.close())
+	}
+
+	if l.async != nil {
+		l.async.close()
+	}
+}
+
+// caller returns the location of the program counter, as the directory, file and line, such as "pocketlog/logger.go:42".
+func caller(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File)), frame.Line)
 }
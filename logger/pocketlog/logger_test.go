@@ -1,8 +1,16 @@
 package pocketlog_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"goprojects/logger/pocketlog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -17,6 +25,81 @@ func ExampleLogger_Debug() {
 	// Output: {"level":"[DEBUG]","message":"Hello, world"}
 }
 
+func ExampleLogger_With() {
+	lgr := pocketlog.New(pocketlog.LevelInfo).With("requestID", "c0ffee")
+	lgr.Info("Game created", "gameID", 42)
+	// Output: {"level":"[INFO]","message":"Game created","requestID":"c0ffee","gameID":42}
+}
+
+func TestLogger_fields(t *testing.T) {
+	tt := map[string]struct {
+		log      func(lgr *pocketlog.Logger)
+		expected string
+	}{
+		"no fields": {
+			log:      func(lgr *pocketlog.Logger) { lgr.Info(infoMessage) },
+			expected: `{"level":"[INFO]","message":"` + infoMessage + "\"}\n",
+		},
+		"key-value pairs": {
+			log: func(lgr *pocketlog.Logger) {
+				lgr.Error(errorMessage, "attempt", 3, "valid", false, "err", errors.New("boom"))
+			},
+			expected: `{"level":"[ERROR]","message":"` + errorMessage + `","attempt":3,"valid":false,"err":"boom"}` + "\n",
+		},
+		"missing keys": {
+			log:      func(lgr *pocketlog.Logger) { lgr.Info(infoMessage, 1, "key", "value", "dangling") },
+			expected: `{"level":"[INFO]","message":"` + infoMessage + `","!BADKEY":1,"key":"value","!BADKEY":"dangling"}` + "\n",
+		},
+		"child logger fields come first": {
+			log: func(lgr *pocketlog.Logger) {
+				lgr.With("gameID", "g1").With("player", "p1").Info(infoMessage, "guess", "hello")
+			},
+			expected: `{"level":"[INFO]","message":"` + infoMessage + `","gameID":"g1","player":"p1","guess":"hello"}` + "\n",
+		},
+		"formatted messages carry the fields": {
+			log: func(lgr *pocketlog.Logger) {
+				lgr.With("gameID", "g1").Infof("guess %d", 2)
+			},
+			expected: `{"level":"[INFO]","message":"guess 2","gameID":"g1"}` + "\n",
+		},
+		"below threshold": {
+			log:      func(lgr *pocketlog.Logger) { lgr.Debug(debugMessage, "key", "value") },
+			expected: "",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			tw := &testWriter{}
+
+			tc.log(pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw)))
+
+			if tw.contents != tc.expected {
+				t.Errorf("invalid contents, expected %q, got %q", tc.expected, tw.contents)
+			}
+		})
+	}
+}
+
+func TestLogger_With_doesNotAffectParent(t *testing.T) {
+	tw := &testWriter{}
+	parent := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw))
+
+	first := parent.With("child", 1)
+	second := parent.With("child", 2)
+
+	parent.Info(infoMessage)
+	first.Info(infoMessage)
+	second.Info(infoMessage)
+
+	expected := `{"level":"[INFO]","message":"` + infoMessage + "\"}\n" +
+		`{"level":"[INFO]","message":"` + infoMessage + `","child":1}` + "\n" +
+		`{"level":"[INFO]","message":"` + infoMessage + `","child":2}` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
 func TestLogger_DebugfInfofErrorf(t *testing.T) {
 	tt := map[string]struct {
 		level    pocketlog.Level
@@ -56,6 +139,114 @@ func TestLogger_DebugfInfofErrorf(t *testing.T) {
 	}
 }
 
+func TestLogger_WithTimestamp(t *testing.T) {
+	tw := &testWriter{}
+	now := time.Date(2024, 4, 25, 12, 30, 0, 123456789, time.UTC)
+
+	lgr := pocketlog.New(pocketlog.LevelInfo,
+		pocketlog.WithOutput(tw),
+		pocketlog.WithTimestamp(),
+		pocketlog.WithClock(func() time.Time { return now }),
+	)
+	lgr.Info(infoMessage, "key", "value")
+
+	expected := `{"time":"2024-04-25T12:30:00.123456789Z","level":"[INFO]","message":"` + infoMessage + `","key":"value"}` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestLogger_WithCaller(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	// Each logging call is on its own line, following this one.
+	calls := []func(lgr *pocketlog.Logger){
+		func(lgr *pocketlog.Logger) { lgr.Debug(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Info(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Error(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Log(pocketlog.LevelInfo, debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Debugf(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Infof(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Errorf(debugMessage) },
+		func(lgr *pocketlog.Logger) { lgr.Logf(pocketlog.LevelInfo, debugMessage) },
+	}
+
+	for i, log := range calls {
+		tw := &testWriter{}
+
+		log(pocketlog.New(pocketlog.LevelDebug, pocketlog.WithOutput(tw), pocketlog.WithCaller()))
+
+		expected := fmt.Sprintf(`"caller":"pocketlog/logger_test.go:%d"`, line+i+3)
+		if !strings.Contains(tw.contents, expected) {
+			t.Errorf("call %d: invalid contents, expected %s, got %q", i, expected, tw.contents)
+		}
+	}
+}
+
+func TestLogger_concurrentWrites(t *testing.T) {
+	const (
+		goroutines = 20
+		messages   = 100
+	)
+
+	lw := &lineWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(lw))
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		// Half of the goroutines log through a child logger, which shares the output of its parent.
+		child := lgr
+		if g%2 == 0 {
+			child = lgr.With("goroutine", g)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range messages {
+				child.Infof("%s #%d", infoMessage, i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if lw.overlaps.Load() != 0 {
+		t.Errorf("expected no concurrent calls to Write, got %d", lw.overlaps.Load())
+	}
+
+	if len(lw.lines) != goroutines*messages {
+		t.Fatalf("expected %d calls to Write, got %d", goroutines*messages, len(lw.lines))
+	}
+
+	for _, line := range lw.lines {
+		if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+			t.Fatalf("expected exactly one complete line per call to Write, got %q", line)
+		}
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("expected a valid JSON line, got %q", line)
+		}
+	}
+}
+
+// lineWriter records every call to Write, and counts the calls that happen while another one is running.
+// It is deliberately not protected by a lock: the logger must not call it concurrently.
+type lineWriter struct {
+	writing  atomic.Bool
+	overlaps atomic.Int32
+	lines    []string
+}
+
+// Write implements the io.Writer interface
+func (lw *lineWriter) Write(p []byte) (n int, err error) {
+	if !lw.writing.CompareAndSwap(false, true) {
+		lw.overlaps.Add(1)
+		return len(p), nil
+	}
+	defer lw.writing.Store(false)
+
+	lw.lines = append(lw.lines, string(p))
+	return len(p), nil
+}
+
 // testWriter is a struct that implements io.Writer.
 // We use it to validate that we can write to a specific output.
 type testWriter struct {
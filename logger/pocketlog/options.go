@@ -1,17 +1,30 @@
 package pocketlog
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // Option defines a functional option to our logger.
 type Option func(*Logger)
 
-// WithOutput returns a configuration function that sets the output of logs.
+// WithOutput returns a configuration function that sets the output of logs. Default is os.Stdout.
 func WithOutput(output io.Writer) Option {
 	return func(lgr *Logger) {
 		lgr.output = output
 	}
 }
 
+// WithSink adds a destination of the messages, which only receives those of the given level or higher,
+// formatted with its own encoder. A nil output defaults to Stdout, and a nil encoder to JSONEncoder.
+// Sinks replace the output and the encoder set with WithOutput and WithEncoder.
+// The threshold of the logger is checked first: a message below it reaches no sink.
+func WithSink(output io.Writer, threshold Level, encoder Encoder) Option {
+	return func(lgr *Logger) {
+		lgr.sinks = append(lgr.sinks, newSink(output, threshold, encoder))
+	}
+}
+
 // WithMaxMessageLength sets the maximum length, in characters, of a message.
 // Use 0 for no maximum length.
 func WithMaxMessageLength(maxMessageLength uint) Option {
@@ -19,3 +32,62 @@ func WithMaxMessageLength(maxMessageLength uint) Option {
 		lgr.maxMessageLength = maxMessageLength
 	}
 }
+
+// WithEncoder sets the format of the messages. Default is JSONEncoder.
+func WithEncoder(encoder Encoder) Option {
+	return func(lgr *Logger) {
+		lgr.encoder = encoder
+	}
+}
+
+// WithAsync makes the logger write asynchronously: messages are stored in a buffer of the given size,
+// and a background goroutine encodes them and writes them to the outputs.
+// Values given as fields must therefore not be modified after logging them.
+// When the buffer is full, the overflow policy decides what happens to new messages.
+// Messages of level Error and above are never dropped. Dropped messages are reported by a warning,
+// with their number as the "dropped" field.
+// Call Flush to wait for the messages to be written, and Close before the program exits.
+// A size of 0 or less keeps the logger synchronous, which is the default.
+func WithAsync(size int, policy OverflowPolicy) Option {
+	return func(lgr *Logger) {
+		lgr.asyncSize = size
+		lgr.overflow = policy
+	}
+}
+
+// WithSampling limits the number of identical messages, with the same level and text, logged during each interval:
+// the first occurrences are logged, then one every thereafter occurrences, or none if thereafter is 0.
+// Errors and fatal messages are never sampled, so that no failure goes unnoticed.
+// At the end of every interval, a message with the same level reports the number of suppressed occurrences,
+// in the "suppressed" field, with the text of the message in the "sampled" field.
+// The sampling runs a background goroutine, stopped by Close. An interval of 0 or less disables the sampling,
+// which is the default.
+func WithSampling(first, thereafter int, interval time.Duration) Option {
+	return func(lgr *Logger) {
+		lgr.sampleFirst = first
+		lgr.sampleThereafter = thereafter
+		lgr.sampleInterval = interval
+	}
+}
+
+// WithTimestamp adds the time of each message, in the RFC3339Nano format.
+func WithTimestamp() Option {
+	return func(lgr *Logger) {
+		lgr.withTimestamp = true
+	}
+}
+
+// WithClock sets the function returning the current time. Default is time.Now.
+// It is mostly useful to test timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(lgr *Logger) {
+		lgr.now = now
+	}
+}
+
+// WithCaller adds the location of the code that logged each message, as its file and line.
+func WithCaller() Option {
+	return func(lgr *Logger) {
+		lgr.withCaller = true
+	}
+}
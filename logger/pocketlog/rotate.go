@@ -0,0 +1,378 @@
+package pocketlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the layout of the time in the names of the backups.
+// It sorts in chronological order.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// RotatingFile is an io.Writer to a file that is rotated when it grows too large, or gets too old.
+// Rotating renames the file to a backup, named after the file and the time of the rotation,
+// such as app-20240102T150405.000000000.log, and creates a new file.
+// If the file can't be opened again after a rotation, the next calls to Write, Rotate and Reopen try to open it.
+// It is safe for concurrent use. Give it to WithOutput.
+type RotatingFile struct {
+	mu   sync.Mutex
+	path string
+	// file is nil when Close was called, or when the file couldn't be opened again.
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	// closed is true once Close was called.
+	closed bool
+
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+}
+
+// RotateOption defines a functional option to a RotatingFile.
+type RotateOption func(*RotatingFile)
+
+// WithMaxSize rotates the file before a write would make it larger than maxSize bytes.
+// A single write larger than maxSize is written to a new file. Use 0 for no maximum size, which is the default.
+func WithMaxSize(maxSize int64) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.maxSize = maxSize
+	}
+}
+
+// WithRotateInterval rotates the file on the first write after it has been open for the given duration.
+// Use 0 to never rotate on time, which is the default.
+func WithRotateInterval(interval time.Duration) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.interval = interval
+	}
+}
+
+// WithMaxBackups keeps only the given number of backups, deleting the oldest ones after each rotation.
+// Use 0 to keep all the backups, which is the default.
+func WithMaxBackups(maxBackups int) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.maxBackups = maxBackups
+	}
+}
+
+// WithCompression compresses the backups with gzip, adding the .gz extension to their names.
+// The compression happens during the write that triggers the rotation.
+func WithCompression() RotateOption {
+	return func(rf *RotatingFile) {
+		rf.compress = true
+	}
+}
+
+// WithRotateClock sets the function returning the current time. Default is time.Now.
+// It is mostly useful to test rotations on time.
+func WithRotateClock(now func() time.Time) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.now = now
+	}
+}
+
+// NewRotatingFile opens the file at path for appending, creating it if needed.
+// Without options, the file is never rotated.
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	rf := &RotatingFile{path: path, now: time.Now}
+
+	for _, configFunc := range opts {
+		configFunc(rf)
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+// Write implements the io.Writer interface. It rotates the file first if needed.
+// If the rotation fails, p is still written to the file whenever possible,
+// and the error of the rotation is returned with the result of the write.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if err := rf.ensureOpen(); err != nil {
+		return 0, err
+	}
+
+	var rotateErr error
+	if rf.shouldRotate(int64(len(p))) {
+		rotateErr = rf.rotate()
+		if rf.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+
+	return n, errors.Join(rotateErr, err)
+}
+
+// Rotate renames the file to a backup and creates a new one, whatever its size and age.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if err := rf.ensureOpen(); err != nil {
+		return err
+	}
+
+	return rf.rotate()
+}
+
+// Reopen closes the file and opens the file at the same path again, creating it if needed.
+// Call it when an external tool, such as logrotate, has moved the file, usually on SIGHUP:
+//
+//	hup := make(chan os.Signal, 1)
+//	signal.Notify(hup, syscall.SIGHUP)
+//	go func() {
+//		for range hup {
+//			_ = rf.Reopen()
+//		}
+//	}()
+func (rf *RotatingFile) Reopen() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return ErrFileClosed
+	}
+
+	if rf.file == nil {
+		// A previous attempt failed: there is nothing to close.
+		return rf.open()
+	}
+
+	if err := rf.file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("unable to close %s: %w", rf.path, err), rf.reopen())
+	}
+
+	return rf.reopen()
+}
+
+// Close closes the file. Writing after Close returns ErrFileClosed.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return nil
+	}
+
+	rf.closed = true
+	if rf.file == nil {
+		return nil
+	}
+
+	err := rf.file.Close()
+	rf.file = nil
+
+	return err
+}
+
+// ensureOpen returns ErrFileClosed after Close, and otherwise opens the file again if a previous attempt failed.
+func (rf *RotatingFile) ensureOpen() error {
+	if rf.closed {
+		return ErrFileClosed
+	}
+
+	if rf.file == nil {
+		return rf.open()
+	}
+
+	return nil
+}
+
+// open opens the file at path for appending, and resets its size and age.
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", rf.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unable to stat %s: %w", rf.path, err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+
+	return nil
+}
+
+// reopen opens the file at path after closing the previous one.
+// If it fails, the file is nil, rather than a closed file, until a later call manages to open it.
+func (rf *RotatingFile) reopen() error {
+	if err := rf.open(); err != nil {
+		rf.file = nil
+		return err
+	}
+
+	return nil
+}
+
+// shouldRotate returns whether the file must be rotated before writing n more bytes.
+// An empty file is never rotated.
+func (rf *RotatingFile) shouldRotate(n int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+
+	if rf.maxSize > 0 && rf.size+n > rf.maxSize {
+		return true
+	}
+
+	return rf.interval > 0 && !rf.now().Before(rf.openedAt.Add(rf.interval))
+}
+
+// rotate closes the file, renames it to a backup, compresses it if required, deletes the extra backups,
+// and opens a new file. When it fails, the file is left open if possible, and nil otherwise.
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		// Keep writing to the same file, rather than losing messages.
+		return errors.Join(fmt.Errorf("unable to close %s: %w", rf.path, err), rf.reopen())
+	}
+
+	backup := rf.backupName(rf.now())
+	if err := os.Rename(rf.path, backup); err != nil {
+		// Keep writing to the same file, rather than losing messages.
+		return errors.Join(fmt.Errorf("unable to rename %s: %w", rf.path, err), rf.reopen())
+	}
+
+	if err := rf.reopen(); err != nil {
+		return err
+	}
+
+	if rf.compress {
+		if err := compressFile(backup); err != nil {
+			return err
+		}
+	}
+
+	return rf.prune()
+}
+
+// backupName returns the name of a backup made at the given time, which isn't used by another file.
+func (rf *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := rf.nameParts()
+
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+
+		// Another rotation happened at the same time: keep the chronological order of the names.
+		t = t.Add(time.Nanosecond)
+	}
+}
+
+// nameParts splits the path of the file into the directory, the prefix of the backups, and the extension.
+func (rf *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir, base := filepath.Split(rf.path)
+	ext = filepath.Ext(base)
+
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// prune deletes the oldest backups, keeping maxBackups of them.
+func (rf *RotatingFile) prune() error {
+	if rf.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := rf.backups()
+	if err != nil {
+		return err
+	}
+
+	for len(backups) > rf.maxBackups {
+		if err = os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("unable to remove backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// backups returns the paths of the backups of the file, from the oldest to the most recent.
+func (rf *RotatingFile) backups() ([]string, error) {
+	dir, prefix, ext := rf.nameParts()
+
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list backups: %w", err)
+	}
+
+	backups := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		if _, err = time.Parse(backupTimeFormat, stamp); err != nil {
+			// Not a backup, only a file with a similar name.
+			continue
+		}
+
+		backups = append(backups, filepath.Join(dir, name))
+	}
+
+	// The names only differ by their time, which sorts in chronological order.
+	slices.SortFunc(backups, func(a, b string) int {
+		return strings.Compare(strings.TrimSuffix(a, ".gz"), strings.TrimSuffix(b, ".gz"))
+	})
+
+	return backups, nil
+}
+
+// compressFile replaces the file at path with its gzipped version, at path.gz.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", path, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", path, err)
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return fmt.Errorf("unable to compress %s: %w", path, err)
+	}
+
+	return os.Remove(path)
+}
+
+// exists returns whether a file exists at path.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,283 @@
+package pocketlog_test
+
+import (
+	"compress/gzip"
+	"goprojects/logger/pocketlog"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_maxSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path, pocketlog.WithMaxSize(10), pocketlog.WithMaxBackups(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err = rf.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Each line is too large to share a file with another one: the first backup was deleted.
+	assertFile(t, path, "fourth\n")
+
+	backups := listBackups(t, dir)
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v", backups)
+	}
+	assertFile(t, filepath.Join(dir, backups[0]), "second\n")
+	assertFile(t, filepath.Join(dir, backups[1]), "third\n")
+}
+
+func TestRotatingFile_interval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	rf, err := pocketlog.NewRotatingFile(path, pocketlog.WithRotateInterval(time.Hour), pocketlog.WithRotateClock(clock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	_, _ = rf.Write([]byte("morning\n"))
+	now = now.Add(59 * time.Minute)
+	_, _ = rf.Write([]byte("still morning\n"))
+	now = now.Add(time.Minute)
+	_, _ = rf.Write([]byte("afternoon\n"))
+
+	assertFile(t, path, "afternoon\n")
+
+	backups := listBackups(t, dir)
+	expected := []string{"app-20240102T160405.000000000.log"}
+	if !slices.Equal(backups, expected) {
+		t.Fatalf("expected backups %v, got %v", expected, backups)
+	}
+	assertFile(t, filepath.Join(dir, backups[0]), "morning\nstill morning\n")
+}
+
+func TestRotatingFile_compression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path, pocketlog.WithCompression())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	_, _ = rf.Write([]byte("compressed\n"))
+	if err = rf.Rotate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	backups := listBackups(t, dir)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("expected one compressed backup, got %v", backups)
+	}
+
+	f, err := os.Open(filepath.Join(dir, backups[0]))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "compressed\n" {
+		t.Errorf("invalid backup contents, expected %q, got %q", "compressed\n", got)
+	}
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(rf))
+	lgr.Info("before")
+
+	// An external tool moves the file, then asks for it to be reopened.
+	if err = os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = rf.Reopen(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lgr.Info("after")
+
+	assertFile(t, path+".1", `{"level":"[INFO]","message":"before"}`+"\n")
+	assertFile(t, path, `{"level":"[INFO]","message":"after"}`+"\n")
+
+	if err = rf.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = rf.Write([]byte("closed\n")); err != pocketlog.ErrFileClosed {
+		t.Errorf("expected ErrFileClosed after Close, got %v", err)
+	}
+}
+
+func TestRotatingFile_rotationFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path, pocketlog.WithMaxSize(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	_, _ = rf.Write([]byte("first\n"))
+
+	// The file is removed behind the back of the writer: it can't be renamed, but it can be created again.
+	if err = os.Remove(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n, err := rf.Write([]byte("second\n"))
+	if err == nil {
+		t.Error("expected the error of the rotation")
+	}
+	if n != len("second\n") {
+		t.Errorf("expected the line to be written anyway, got %d bytes written", n)
+	}
+
+	assertFile(t, path, "second\n")
+}
+
+func TestRotatingFile_reopenFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path, pocketlog.WithMaxSize(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	_, _ = rf.Write([]byte("first\n"))
+
+	// Without its directory, the file can be neither renamed nor created again.
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = rf.Write([]byte("second\n")); err == nil {
+		t.Error("expected the error of the rotation")
+	}
+
+	// The file isn't closed: the next writes try to open it again.
+	if _, err = rf.Write([]byte("third\n")); err == nil || err == pocketlog.ErrFileClosed {
+		t.Errorf("expected the error of the opening, got %v", err)
+	}
+
+	if err = os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = rf.Write([]byte("fourth\n")); err != nil {
+		t.Fatalf("expected the writes to recover once the directory is back, got %v", err)
+	}
+
+	assertFile(t, path, "fourth\n")
+}
+
+func TestRotatingFile_Reopen_recovery(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "app.log")
+
+	rf, err := pocketlog.NewRotatingFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	// Without its directory, the file can't be created again.
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = rf.Reopen(); err == nil {
+		t.Fatal("expected the error of the opening")
+	}
+
+	if err = os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = rf.Reopen(); err != nil {
+		t.Fatalf("expected Reopen to recover once the directory is back, got %v", err)
+	}
+
+	if _, err = rf.Write([]byte("back\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertFile(t, path, "back\n")
+
+	if err = rf.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = rf.Reopen(); err != pocketlog.ErrFileClosed {
+		t.Errorf("expected ErrFileClosed after Close, got %v", err)
+	}
+}
+
+// listBackups returns the names of the files of the directory, other than app.log, sorted by name.
+func listBackups(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			backups = append(backups, entry.Name())
+		}
+	}
+
+	return backups
+}
+
+// assertFile checks the contents of the file at path.
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != expected {
+		t.Errorf("invalid contents of %s, expected %q, got %q", filepath.Base(path), expected, got)
+	}
+}
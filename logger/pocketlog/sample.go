@@ -0,0 +1,155 @@
+package pocketlog
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// suppressedMessage is the message of the records summarizing the messages suppressed by sampling.
+const suppressedMessage = "pocketlog: messages suppressed by sampling"
+
+// sampleKey identifies identical messages.
+type sampleKey struct {
+	level   Level
+	message string
+}
+
+// suppressed is the number of occurrences of a message that weren't logged during an interval.
+type suppressed struct {
+	sampleKey
+	count int
+}
+
+// sampler counts the occurrences of each message during an interval, and decides which ones are logged.
+type sampler struct {
+	first      int
+	thereafter int
+	interval   time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+	// closed is true once the sampler is stopped: every message is then logged.
+	closed bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSampler returns a sampler whose first interval starts now.
+func newSampler(first, thereafter int, interval time.Duration, now time.Time) *sampler {
+	return &sampler{
+		first:       first,
+		thereafter:  thereafter,
+		interval:    interval,
+		windowStart: now,
+		counts:      make(map[sampleKey]int),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// allow registers an occurrence of a message, and returns whether it should be logged.
+// If the interval is over, it also returns the summary of the previous one.
+func (s *sampler) allow(lvl Level, msg string, now time.Time) (bool, []suppressed) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return true, nil
+	}
+
+	summary := s.rollover(now)
+
+	key := sampleKey{level: lvl, message: msg}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.first {
+		return true, summary
+	}
+
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0, summary
+}
+
+// rollover starts a new interval if the current one is over, and returns the messages it suppressed,
+// sorted by level and message. It must be called while holding the lock.
+func (s *sampler) rollover(now time.Time) []suppressed {
+	if now.Before(s.windowStart.Add(s.interval)) {
+		return nil
+	}
+
+	summary := s.suppressed()
+	clear(s.counts)
+	s.windowStart = now
+
+	return summary
+}
+
+// suppressed returns the number of suppressed occurrences of each message during the current interval.
+// It must be called while holding the lock.
+func (s *sampler) suppressed() []suppressed {
+	var summary []suppressed
+	for key, n := range s.counts {
+		if n <= s.first {
+			continue
+		}
+
+		count := n - s.first
+		if s.thereafter > 0 {
+			count -= count / s.thereafter
+		}
+
+		summary = append(summary, suppressed{sampleKey: key, count: count})
+	}
+
+	slices.SortFunc(summary, func(a, b suppressed) int {
+		return cmp.Or(cmp.Compare(a.level, b.level), cmp.Compare(a.message, b.message))
+	})
+
+	return summary
+}
+
+// run calls report with the summary of every interval, until stopped.
+func (s *sampler) run(now func() time.Time, report func([]suppressed)) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			summary := s.rollover(now())
+			s.mu.Unlock()
+
+			report(summary)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// close stops the background goroutine, and returns the summary of the current interval.
+// It is safe to call several times.
+func (s *sampler) close() []suppressed {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	summary := s.suppressed()
+	clear(s.counts)
+	s.closed = true
+
+	return summary
+}
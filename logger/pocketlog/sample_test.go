@@ -0,0 +1,88 @@
+package pocketlog_test
+
+import (
+	"goprojects/logger/pocketlog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_WithSampling(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tw := &testWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo,
+		pocketlog.WithOutput(tw),
+		pocketlog.WithClock(clock),
+		pocketlog.WithSampling(2, 3, time.Hour),
+	)
+	defer lgr.Close()
+
+	// Occurrences 1 and 2 are logged, then 5 and 8: 6 out of 10 are suppressed.
+	// Messages are identical regardless of their fields.
+	for i := 1; i <= 10; i++ {
+		lgr.Infof("wrong turn at %s", "x")
+		lgr.Warn("hot", "i", i)
+	}
+
+	now = now.Add(time.Hour)
+	lgr.Warn("hot", "i", 11)
+
+	expected := []string{
+		`{"level":"[INFO]","message":"wrong turn at x"}`,
+		`{"level":"[WARN]","message":"hot","i":1}`,
+		`{"level":"[INFO]","message":"wrong turn at x"}`,
+		`{"level":"[WARN]","message":"hot","i":2}`,
+		`{"level":"[INFO]","message":"wrong turn at x"}`,
+		`{"level":"[WARN]","message":"hot","i":5}`,
+		`{"level":"[INFO]","message":"wrong turn at x"}`,
+		`{"level":"[WARN]","message":"hot","i":8}`,
+		`{"level":"[INFO]","message":"pocketlog: messages suppressed by sampling","sampled":"wrong turn at x","suppressed":6}`,
+		`{"level":"[WARN]","message":"pocketlog: messages suppressed by sampling","sampled":"hot","suppressed":6}`,
+		`{"level":"[WARN]","message":"hot","i":11}`,
+	}
+
+	got := strings.Split(strings.TrimSuffix(tw.contents, "\n"), "\n")
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("invalid contents, expected %q, got %q", expected, got)
+	}
+}
+
+func TestLogger_WithSampling_Close(t *testing.T) {
+	tw := &testWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithSampling(1, 0, time.Hour))
+
+	for range 3 {
+		lgr.Info(infoMessage)
+	}
+
+	lgr.Close()
+
+	// Once closed, the logger doesn't sample anymore.
+	lgr.Info(infoMessage)
+
+	expected := `{"level":"[INFO]","message":"` + infoMessage + `"}` + "\n" +
+		`{"level":"[INFO]","message":"pocketlog: messages suppressed by sampling","sampled":"` + infoMessage + `","suppressed":2}` + "\n" +
+		`{"level":"[INFO]","message":"` + infoMessage + `"}` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestLogger_WithSampling_errorsNotSampled(t *testing.T) {
+	tw := &testWriter{}
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithSampling(1, 0, time.Hour))
+	defer lgr.Close()
+
+	for range 2 {
+		lgr.Error(errorMessage)
+		lgr.Log(pocketlog.LevelFatal, errorMessage)
+	}
+
+	expected := strings.Repeat(`{"level":"[ERROR]","message":"`+errorMessage+`"}`+"\n"+
+		`{"level":"[FATAL]","message":"`+errorMessage+`"}`+"\n", 2)
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
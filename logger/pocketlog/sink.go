@@ -0,0 +1,49 @@
+package pocketlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// sink is a destination of the messages, with its own threshold and format.
+type sink struct {
+	// mu prevents concurrent writes to the output.
+	mu        *sync.Mutex
+	output    io.Writer
+	threshold Level
+	encoder   Encoder
+}
+
+// newSink returns a sink, defaulting to Stdout and JSON.
+func newSink(output io.Writer, threshold Level, encoder Encoder) sink {
+	if output == nil {
+		output = os.Stdout
+	}
+
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+
+	return sink{mu: new(sync.Mutex), output: output, threshold: threshold, encoder: encoder}
+}
+
+// write encodes the record, and writes it to the output as a complete line, in a single call,
+// if its level is high enough.
+func (s sink) write(r Record) {
+	if r.Level < s.threshold {
+		return
+	}
+
+	line, err := s.encoder.Encode(r)
+	if err != nil {
+		line = []byte(fmt.Sprintf("unable to format message for %v", r.Message))
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, _ = s.output.Write(line)
+}
@@ -0,0 +1,62 @@
+package pocketlog_test
+
+import (
+	"goprojects/logger/pocketlog"
+	"testing"
+)
+
+func TestLogger_WithSink(t *testing.T) {
+	stderr := &testWriter{}
+	file := &testWriter{}
+	ignored := &testWriter{}
+
+	lgr := pocketlog.New(pocketlog.LevelDebug,
+		pocketlog.WithOutput(ignored),
+		pocketlog.WithSink(stderr, pocketlog.LevelError, pocketlog.LogfmtEncoder{}),
+		pocketlog.WithSink(file, pocketlog.LevelTrace, nil),
+	)
+
+	lgr.Trace("below the threshold of the logger")
+	lgr.Debug(debugMessage)
+	lgr.With("child", true).Error(errorMessage, "code", 42)
+
+	expectedErrors := `level=[ERROR] message="` + errorMessage + `" child=true code=42` + "\n"
+	if stderr.contents != expectedErrors {
+		t.Errorf("invalid contents of the error sink, expected %q, got %q", expectedErrors, stderr.contents)
+	}
+
+	expectedFile := `{"level":"[DEBUG]","message":"` + debugMessage + `"}` + "\n" +
+		`{"level":"[ERROR]","message":"` + errorMessage + `","child":true,"code":42}` + "\n"
+	if file.contents != expectedFile {
+		t.Errorf("invalid contents of the file sink, expected %q, got %q", expectedFile, file.contents)
+	}
+
+	if ignored.contents != "" {
+		t.Errorf("expected sinks to replace the output, got %q", ignored.contents)
+	}
+}
+
+func TestLogger_WithSink_async(t *testing.T) {
+	warnings := &testWriter{}
+	all := &testWriter{}
+
+	lgr := pocketlog.New(pocketlog.LevelInfo,
+		pocketlog.WithSink(warnings, pocketlog.LevelWarn, nil),
+		pocketlog.WithSink(all, pocketlog.LevelInfo, nil),
+		pocketlog.WithAsync(4, pocketlog.OverflowBlock),
+	)
+
+	lgr.Info(infoMessage)
+	lgr.Warn(errorMessage)
+	lgr.Close()
+
+	expectedWarnings := `{"level":"[WARN]","message":"` + errorMessage + `"}` + "\n"
+	if warnings.contents != expectedWarnings {
+		t.Errorf("invalid contents of the warning sink, expected %q, got %q", expectedWarnings, warnings.contents)
+	}
+
+	expectedAll := `{"level":"[INFO]","message":"` + infoMessage + `"}` + "\n" + expectedWarnings
+	if all.contents != expectedAll {
+		t.Errorf("invalid contents of the info sink, expected %q, got %q", expectedAll, all.contents)
+	}
+}
@@ -0,0 +1,129 @@
+package pocketlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler is a slog.Handler that logs through a Logger, so that code using log/slog
+// shares the configuration of the Logger: threshold, sinks, timestamp and caller.
+// slog levels are mapped onto the closest pocketlog level. Groups are flattened into the keys
+// of the fields, separated by dots, such as "request.method".
+type Handler struct {
+	lgr *Logger
+	// prefix is the concatenation of the current groups, each followed by a dot.
+	prefix string
+	fields []Field
+}
+
+// NewHandler returns a slog.Handler logging through lgr.
+func NewHandler(lgr *Logger) *Handler {
+	return &Handler{lgr: lgr}
+}
+
+// NewSlogLogger returns a slog.Logger logging through lgr.
+func NewSlogLogger(lgr *Logger) *slog.Logger {
+	return slog.New(NewHandler(lgr))
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.lgr.enabled(fromSlogLevel(level))
+}
+
+// Handle implements the slog.Handler interface.
+func (h *Handler) Handle(_ context.Context, sr slog.Record) error {
+	if !h.lgr.sample(fromSlogLevel(sr.Level), sr.Message) {
+		return nil
+	}
+
+	fields := make([]Field, 0, len(h.lgr.fields)+len(h.fields)+sr.NumAttrs())
+	fields = append(fields, h.lgr.fields...)
+	fields = append(fields, h.fields...)
+	sr.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	r := Record{
+		Level:   fromSlogLevel(sr.Level),
+		Message: sr.Message,
+		Fields:  fields,
+	}
+
+	if h.lgr.withTimestamp {
+		r.Time = sr.Time
+	}
+
+	if h.lgr.withCaller && sr.PC != 0 {
+		r.Caller = caller(sr.PC)
+	}
+
+	h.lgr.write(r)
+
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		child.fields = appendAttr(child.fields, h.prefix, attr)
+	}
+
+	return &child
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	child := *h
+	child.prefix = h.prefix + name + "."
+
+	return &child
+}
+
+// appendAttr appends an attribute to the fields, with its key prefixed by the current groups.
+// Groups are flattened, and empty attributes are ignored, as required by slog.Handler.
+func appendAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, a := range attr.Value.Group() {
+			fields = appendAttr(fields, prefix, a)
+		}
+
+		return fields
+	}
+
+	return append(fields, Field{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+// fromSlogLevel returns the pocketlog level matching a slog level.
+// Levels between two pocketlog levels are rounded down, and levels below debug are trace.
+// Nothing is mapped onto LevelFatal, as slog doesn't expect the program to stop.
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarn
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	case level >= slog.LevelDebug:
+		return LevelDebug
+	default:
+		return LevelTrace
+	}
+}
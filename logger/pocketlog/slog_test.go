@@ -0,0 +1,96 @@
+package pocketlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"goprojects/logger/pocketlog"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+)
+
+func TestHandler_slogtest(t *testing.T) {
+	var buf bytes.Buffer
+
+	lgr := pocketlog.New(pocketlog.LevelDebug, pocketlog.WithOutput(&buf), pocketlog.WithTimestamp())
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+			if len(line) == 0 {
+				continue
+			}
+
+			var flat map[string]any
+			if err := json.Unmarshal(line, &flat); err != nil {
+				t.Fatalf("invalid JSON %q: %s", line, err)
+			}
+
+			ms = append(ms, unflatten(flat))
+		}
+		return ms
+	}
+
+	err := slogtest.TestHandler(pocketlog.NewHandler(lgr), results)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// unflatten turns the dotted keys of the fields back into nested groups,
+// and renames the message key to the one slogtest expects.
+func unflatten(flat map[string]any) map[string]any {
+	m := make(map[string]any)
+
+	for key, value := range flat {
+		if key == "message" {
+			key = slog.MessageKey
+		}
+
+		groups := strings.Split(key, ".")
+		current := m
+		for _, group := range groups[:len(groups)-1] {
+			next, ok := current[group].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				current[group] = next
+			}
+			current = next
+		}
+		current[groups[len(groups)-1]] = value
+	}
+
+	return m
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	tw := &testWriter{}
+
+	lgr := pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw)).With("service", "httptordle")
+	logger := pocketlog.NewSlogLogger(lgr)
+
+	logger.Debug("hidden")
+	logger.With("gameID", "g1").WithGroup("guess").Info("valid", "word", "hello", slog.Group("hint", "a", 1))
+	logger.Warn("slow", "duration", time.Second)
+	logger.Error("failed")
+
+	expected := `{"level":"[INFO]","message":"valid","service":"httptordle","gameID":"g1","guess.word":"hello","guess.hint.a":1}` + "\n" +
+		`{"level":"[WARN]","message":"slow","service":"httptordle","duration":1000000000}` + "\n" +
+		`{"level":"[ERROR]","message":"failed","service":"httptordle"}` + "\n"
+	if tw.contents != expected {
+		t.Errorf("invalid contents, expected %q, got %q", expected, tw.contents)
+	}
+}
+
+func TestHandler_caller(t *testing.T) {
+	tw := &testWriter{}
+
+	logger := pocketlog.NewSlogLogger(pocketlog.New(pocketlog.LevelInfo, pocketlog.WithOutput(tw), pocketlog.WithCaller()))
+	logger.Info("here")
+
+	if !strings.Contains(tw.contents, `"caller":"pocketlog/slog_test.go:`) {
+		t.Errorf("caller should be the test, got %q", tw.contents)
+	}
+}